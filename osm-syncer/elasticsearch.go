@@ -0,0 +1,86 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	elasticsearch "github.com/elastic/go-elasticsearch/v8"
+)
+
+// newESClient creates an Elasticsearch client from environment variables
+func newESClient() (*elasticsearch.Client, error) {
+	cfg, err := esConfig()
+	if err != nil {
+		return nil, err
+	}
+	return elasticsearch.NewClient(cfg)
+}
+
+// esConfig builds the Elasticsearch client configuration from environment
+// variables. ES_API_KEY takes precedence over ES_USERNAME/ES_PASSWORD basic auth.
+func esConfig() (elasticsearch.Config, error) {
+	esURL := os.Getenv("ELASTICSEARCH_URL")
+	if esURL == "" {
+		esURL = "http://localhost:9200"
+	}
+
+	tlsConfig, err := esTLSConfig()
+	if err != nil {
+		return elasticsearch.Config{}, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	cfg := elasticsearch.Config{
+		Addresses: []string{esURL},
+		Transport: transport,
+	}
+
+	switch {
+	case os.Getenv("ES_API_KEY") != "":
+		cfg.APIKey = os.Getenv("ES_API_KEY")
+		log.Println("[osm-syncer] Elasticsearch auth: API key")
+	case os.Getenv("ES_USERNAME") != "":
+		cfg.Username = os.Getenv("ES_USERNAME")
+		cfg.Password = os.Getenv("ES_PASSWORD")
+		log.Printf("[osm-syncer] Elasticsearch auth: basic (user %s)", cfg.Username)
+	default:
+		log.Println("[osm-syncer] Elasticsearch auth: none")
+	}
+
+	return cfg, nil
+}
+
+// esTLSConfig builds the TLS configuration for Elasticsearch. ES_CA_CERT_PATH
+// adds a PEM CA certificate to the trusted roots; ES_TLS_SKIP_VERIFY=true
+// disables certificate verification and is meant for development only.
+func esTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if path := os.Getenv("ES_CA_CERT_PATH"); path != "" {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA certificate: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", path)
+		}
+		tlsConfig.RootCAs = pool
+		log.Printf("[osm-syncer] Elasticsearch TLS: trusting CA certificate %s", path)
+	}
+
+	if os.Getenv("ES_TLS_SKIP_VERIFY") == "true" {
+		tlsConfig.InsecureSkipVerify = true
+		log.Println("[osm-syncer] WARNING: Elasticsearch TLS certificate verification disabled (ES_TLS_SKIP_VERIFY=true)")
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	elasticsearch "github.com/elastic/go-elasticsearch/v8"
+)
+
+// recordingTransport answers every request with an empty Elasticsearch
+// response and records the Authorization header it was sent
+type recordingTransport struct {
+	authorization string
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.authorization = req.Header.Get("Authorization")
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"X-Elastic-Product": []string{"Elasticsearch"}, "Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestESConfigAuthorization(t *testing.T) {
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte("elastic:secret"))
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"none", nil, ""},
+		{"api key", map[string]string{"ES_API_KEY": "a2V5OnNlY3JldA=="}, "APIKey a2V5OnNlY3JldA=="},
+		{"basic", map[string]string{"ES_USERNAME": "elastic", "ES_PASSWORD": "secret"}, basic},
+		{"api key wins over basic", map[string]string{"ES_API_KEY": "a2V5", "ES_USERNAME": "elastic", "ES_PASSWORD": "secret"}, "APIKey a2V5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"ELASTICSEARCH_URL", "ES_API_KEY", "ES_USERNAME", "ES_PASSWORD", "ES_CA_CERT_PATH", "ES_TLS_SKIP_VERIFY"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			cfg, err := esConfig()
+			if err != nil {
+				t.Fatalf("esConfig: %v", err)
+			}
+			transport := &recordingTransport{}
+			cfg.Transport = transport
+
+			client, err := elasticsearch.NewClient(cfg)
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			res, err := client.Info()
+			if err != nil {
+				t.Fatalf("Info: %v", err)
+			}
+			res.Body.Close()
+
+			if transport.authorization != tt.want {
+				t.Errorf("Authorization = %q, want %q", transport.authorization, tt.want)
+			}
+		})
+	}
+}
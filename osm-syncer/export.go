@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	elasticsearch "github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+// exportScrollKeepAlive is how long the scroll context stays open between pages
+const exportScrollKeepAlive = time.Minute
+
+// exportBatchSize is the number of documents fetched per scroll page
+const exportBatchSize = 1000
+
+// exportedDocument is one line of an NDJSON index backup
+type exportedDocument struct {
+	ID     string          `json:"_id"`
+	Source json.RawMessage `json:"_source"`
+}
+
+// scrollPage is one page of scroll search results
+type scrollPage struct {
+	ScrollID string `json:"_scroll_id"`
+	Hits     struct {
+		Hits []exportedDocument `json:"hits"`
+	} `json:"hits"`
+}
+
+// exportIndex writes every document in index to outputPath as NDJSON, one
+// {"_id", "_source"} object per line, using the scroll API, and returns the
+// number of documents written. The backup is written to a temporary file and
+// renamed on success, so a failed export never leaves a partial backup.
+func exportIndex(ctx context.Context, esClient *elasticsearch.Client, index, outputPath string) (int, error) {
+	tmpPath := outputPath + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return 0, fmt.Errorf("error creating backup file: %w", err)
+	}
+	defer os.Remove(tmpPath)
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+
+	res, err := esClient.Search(
+		esClient.Search.WithContext(ctx),
+		esClient.Search.WithIndex(index),
+		esClient.Search.WithSize(exportBatchSize),
+		esClient.Search.WithSort("_doc"),
+		esClient.Search.WithScroll(exportScrollKeepAlive),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error starting scroll: %w", err)
+	}
+	page, err := decodeScrollPage(res)
+	if err != nil {
+		return 0, err
+	}
+
+	scrollID := page.ScrollID
+	defer func() { clearScroll(esClient, scrollID) }()
+
+	total := 0
+	for len(page.Hits.Hits) > 0 {
+		for _, doc := range page.Hits.Hits {
+			if err := encoder.Encode(doc); err != nil {
+				return total, fmt.Errorf("error writing backup: %w", err)
+			}
+			total++
+		}
+
+		res, err := esClient.Scroll(
+			esClient.Scroll.WithContext(ctx),
+			esClient.Scroll.WithScrollID(scrollID),
+			esClient.Scroll.WithScroll(exportScrollKeepAlive),
+		)
+		if err != nil {
+			return total, fmt.Errorf("error fetching scroll page: %w", err)
+		}
+		if page, err = decodeScrollPage(res); err != nil {
+			return total, err
+		}
+		scrollID = page.ScrollID
+	}
+
+	if err := writer.Flush(); err != nil {
+		return total, fmt.Errorf("error writing backup: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return total, fmt.Errorf("error closing backup file: %w", err)
+	}
+	if err := os.Rename(tmpPath, outputPath); err != nil {
+		return total, fmt.Errorf("error saving backup file: %w", err)
+	}
+	return total, nil
+}
+
+// decodeScrollPage checks a search or scroll response and parses its page
+func decodeScrollPage(res *esapi.Response) (*scrollPage, error) {
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+
+	var page scrollPage
+	if err := json.NewDecoder(res.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+	return &page, nil
+}
+
+// clearScroll releases the server-side scroll context
+func clearScroll(esClient *elasticsearch.Client, scrollID string) {
+	if scrollID == "" {
+		return
+	}
+	res, err := esClient.ClearScroll(esClient.ClearScroll.WithScrollID(scrollID))
+	if err != nil {
+		return
+	}
+	res.Body.Close()
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"log"
+
+	elasticsearch "github.com/elastic/go-elasticsearch/v8"
+)
+
+// orphanedDocuments counts documents whose parent was not found in the last
+// consistency check; served at /debug/vars on METRICS_PORT
+var orphanedDocuments = expvar.NewInt("hierarchy_orphaned_documents")
+
+// hierarchyLevel describes one parent/child relationship in the admin hierarchy
+type hierarchyLevel struct {
+	childName   string
+	childLevel  int
+	parentField string
+	parentLevel int
+}
+
+// Nepal's admin hierarchy: ward (9) -> municipality (7) -> district (6) -> province (4)
+var hierarchyLevels = []hierarchyLevel{
+	{childName: "ward", childLevel: 9, parentField: "municipality", parentLevel: 7},
+	{childName: "municipality", childLevel: 7, parentField: "district", parentLevel: 6},
+	{childName: "district", childLevel: 6, parentField: "province", parentLevel: 4},
+}
+
+// validateHierarchyConsistency checks that every admin document's parent field
+// refers to a parent document that exists in the index. Orphans are logged as
+// warnings and counted in the hierarchy_orphaned_documents expvar.
+func validateHierarchyConsistency(esClient *elasticsearch.Client, index string) error {
+	log.Println("[osm-syncer] Validating admin hierarchy consistency...")
+
+	total := 0
+	for _, level := range hierarchyLevels {
+		parents, err := termCounts(esClient, index, level.parentLevel, level.parentField)
+		if err != nil {
+			return fmt.Errorf("error loading %s names: %w", level.parentField, err)
+		}
+
+		children, err := termCounts(esClient, index, level.childLevel, level.parentField)
+		if err != nil {
+			return fmt.Errorf("error loading %s parents: %w", level.childName, err)
+		}
+
+		for name, count := range children {
+			if _, ok := parents[name]; ok {
+				continue
+			}
+			log.Printf("[osm-syncer] WARNING: %d %s document(s) reference missing %s %q",
+				count, level.childName, level.parentField, name)
+			total += count
+		}
+	}
+
+	orphanedDocuments.Set(int64(total))
+	log.Printf("[osm-syncer] Hierarchy validation complete: %d orphaned document(s)", total)
+	return nil
+}
+
+// termCounts returns the document count per distinct value of field for admin
+// boundaries at the given admin level
+func termCounts(esClient *elasticsearch.Client, index string, adminLevel int, field string) (map[string]int, error) {
+	query := map[string]interface{}{
+		"size": 0,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []map[string]interface{}{
+					{"term": map[string]interface{}{"entity_type": "admin_boundary"}},
+					{"term": map[string]interface{}{"admin_level": adminLevel}},
+				},
+			},
+		},
+		"aggs": map[string]interface{}{
+			"values": map[string]interface{}{
+				"terms": map[string]interface{}{
+					"field": field + ".keyword",
+					"size":  10000,
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+		return nil, fmt.Errorf("error encoding query: %w", err)
+	}
+
+	res, err := esClient.Search(
+		esClient.Search.WithIndex(index),
+		esClient.Search.WithBody(&buf),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error executing search: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+
+	var esResponse struct {
+		Aggregations struct {
+			Values struct {
+				Buckets []struct {
+					Key      string `json:"key"`
+					DocCount int    `json:"doc_count"`
+				} `json:"buckets"`
+			} `json:"values"`
+		} `json:"aggregations"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&esResponse); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	counts := make(map[string]int, len(esResponse.Aggregations.Values.Buckets))
+	for _, bucket := range esResponse.Aggregations.Values.Buckets {
+		counts[bucket.Key] = bucket.DocCount
+	}
+	return counts, nil
+}
@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strconv"
 	"time"
+
+	elasticsearch "github.com/elastic/go-elasticsearch/v8"
 )
 
 // getEnvInt retrieves an integer environment variable or returns a default
@@ -22,7 +27,7 @@ func getEnvInt(key string, defaultVal int) int {
 // - Fetch OSM data for Nepal
 // - Parse and transform the data
 // - Index into Elasticsearch
-func dummySync() {
+func dummySync(esClient *elasticsearch.Client, index string) {
 	log.Println("[osm-syncer] ========================================")
 	log.Println("[osm-syncer] Starting dummy sync execution...")
 	log.Println("[osm-syncer] Simulating OSM data fetch for Nepal...")
@@ -33,13 +38,43 @@ func dummySync() {
 	log.Println("[osm-syncer] Simulating data transformation...")
 	time.Sleep(1 * time.Second)
 
+	// Keep a rollback copy of the current index before overwriting it
+	if backupDir := os.Getenv("ES_BACKUP_DIR"); backupDir != "" {
+		if err := backupIndex(esClient, index, backupDir); err != nil {
+			log.Printf("[osm-syncer] Backup failed, skipping sync: %v", err)
+			return
+		}
+	}
+
 	log.Println("[osm-syncer] Simulating Elasticsearch indexing...")
+	if res, err := esClient.Info(); err != nil {
+		log.Printf("[osm-syncer] Elasticsearch unreachable: %v", err)
+	} else {
+		res.Body.Close()
+	}
 	time.Sleep(1 * time.Second)
 
+	if err := validateHierarchyConsistency(esClient, index); err != nil {
+		log.Printf("[osm-syncer] Hierarchy validation failed: %v", err)
+	}
+
 	log.Println("[osm-syncer] dummy sync executed")
 	log.Println("[osm-syncer] ========================================")
 }
 
+// backupIndex exports index to a timestamped NDJSON file in dir
+func backupIndex(esClient *elasticsearch.Client, index, dir string) error {
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.ndjson", index, time.Now().UTC().Format("20060102T150405Z")))
+	log.Printf("[osm-syncer] Backing up %s to %s...", index, path)
+
+	count, err := exportIndex(context.Background(), esClient, index, path)
+	if err != nil {
+		return err
+	}
+	log.Printf("[osm-syncer] Backed up %d document(s)", count)
+	return nil
+}
+
 func main() {
 	// Get sync interval from environment (default: 5 minutes)
 	syncIntervalMinutes := getEnvInt("SYNC_INTERVAL_MINUTES", 5)
@@ -49,9 +84,24 @@ func main() {
 	log.Printf("[osm-syncer] Sync interval: %v", syncInterval)
 	log.Printf("[osm-syncer] Elasticsearch URL: %s", os.Getenv("ELASTICSEARCH_URL"))
 
+	esIndex := os.Getenv("ELASTICSEARCH_INDEX")
+	if esIndex == "" {
+		esIndex = "nepal_locations"
+	}
+
+	// Metrics such as hierarchy_orphaned_documents; METRICS_PORT=0 disables them
+	if port := getEnvInt("METRICS_PORT", 9090); port > 0 {
+		serveMetrics(port)
+	}
+
+	esClient, err := newESClient()
+	if err != nil {
+		log.Fatalf("[osm-syncer] Error creating Elasticsearch client: %v", err)
+	}
+
 	// Run initial sync immediately
 	log.Println("[osm-syncer] Running initial sync...")
-	dummySync()
+	dummySync(esClient, esIndex)
 
 	// Create ticker for periodic syncs
 	ticker := time.NewTicker(syncInterval)
@@ -61,7 +111,7 @@ func main() {
 
 	// Run periodic syncs
 	for range ticker.C {
-		dummySync()
+		dummySync(esClient, esIndex)
 		log.Printf("[osm-syncer] Waiting for next sync in %v...", syncInterval)
 	}
 }
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"expvar"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// metricsHandler serves the expvar metrics (e.g. hierarchy_orphaned_documents) at /debug/vars
+func metricsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// serveMetrics serves the metrics on port in the background. A failure to
+// listen is logged but does not stop the syncer.
+func serveMetrics(port int) {
+	addr := fmt.Sprintf(":%d", port)
+	log.Printf("[osm-syncer] Serving metrics on %s/debug/vars", addr)
+	go func() {
+		if err := http.ListenAndServe(addr, metricsHandler()); err != nil {
+			log.Printf("[osm-syncer] Metrics server stopped: %v", err)
+		}
+	}()
+}
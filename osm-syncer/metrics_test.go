@@ -0,0 +1,27 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetricsHandlerServesOrphanCount(t *testing.T) {
+	orphanedDocuments.Set(3)
+	defer orphanedDocuments.Set(0)
+
+	rec := httptest.NewRecorder()
+	metricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+
+	var vars map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &vars); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got := string(vars["hierarchy_orphaned_documents"]); got != "3" {
+		t.Errorf("hierarchy_orphaned_documents = %s, want 3", got)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import "math"
+
+// OSMNode is an OSM node with its coordinates
+type OSMNode struct {
+	ID  int64
+	Lat float64
+	Lon float64
+}
+
+// OSMMember is a member of an OSM relation
+type OSMMember struct {
+	Type string // node, way or relation
+	Ref  int64
+	Role string
+}
+
+// OSMRelation is an OSM relation such as a type=multipolygon or
+// type=boundary admin boundary
+type OSMRelation struct {
+	ID      int64
+	Tags    map[string]string
+	Members []OSMMember
+}
+
+// GeoJSONGeometry is a GeoJSON MultiPolygon, indexed as a geo_shape.
+// Coordinates are [polygon][ring][point][lon, lat]; each polygon's first ring
+// is its outer boundary and the rest are holes.
+type GeoJSONGeometry struct {
+	Type        string           `json:"type"`
+	Coordinates [][][][2]float64 `json:"coordinates"`
+}
+
+// assembleMultipolygon builds a relation's MultiPolygon from its member ways,
+// following the OSM convention that role=outer ways form outer boundaries and
+// role=inner ways form holes (an empty role counts as outer). Ways split into
+// several segments are joined end to end into closed rings. Each hole is
+// assigned to the smallest outer ring containing it, so an island inside a
+// lake inside a larger area nests correctly. Outer rings are wound
+// counter-clockwise and holes clockwise, as geo_shape and RFC 7946 expect.
+// Returns nil when no closed outer ring can be built, e.g. for a relation
+// missing some of its ways.
+func assembleMultipolygon(relation OSMRelation, wayMap map[int64][]OSMNode) *GeoJSONGeometry {
+	var outerWays, innerWays [][]OSMNode
+	for _, member := range relation.Members {
+		if member.Type != "way" {
+			continue
+		}
+		nodes, ok := wayMap[member.Ref]
+		if !ok || len(nodes) < 2 {
+			continue
+		}
+		if member.Role == "inner" {
+			innerWays = append(innerWays, nodes)
+		} else {
+			outerWays = append(outerWays, nodes)
+		}
+	}
+
+	outers := joinRings(outerWays)
+	if len(outers) == 0 {
+		return nil
+	}
+
+	polygons := make([][][][2]float64, len(outers))
+	for i, outer := range outers {
+		polygons[i] = [][][2]float64{orientRing(outer, true)}
+	}
+
+	for _, inner := range joinRings(innerWays) {
+		best, bestArea := -1, math.Inf(1)
+		for i, outer := range outers {
+			area := math.Abs(signedArea(outer))
+			if area < bestArea && pointInRing(inner[0], outer) {
+				best, bestArea = i, area
+			}
+		}
+		// A hole outside every outer ring is invalid data and is dropped
+		if best >= 0 {
+			polygons[best] = append(polygons[best], orientRing(inner, false))
+		}
+	}
+
+	return &GeoJSONGeometry{Type: "MultiPolygon", Coordinates: polygons}
+}
+
+// joinRings joins way segments that share end nodes into closed rings of
+// [lon, lat] points. Segments that cannot be closed are dropped.
+func joinRings(ways [][]OSMNode) [][][2]float64 {
+	remaining := append([][]OSMNode(nil), ways...)
+	var rings [][][2]float64
+
+	for len(remaining) > 0 {
+		ring := append([]OSMNode(nil), remaining[0]...)
+		remaining = remaining[1:]
+
+		for ring[0].ID != ring[len(ring)-1].ID {
+			joined := false
+			for i, way := range remaining {
+				first, last := way[0].ID, way[len(way)-1].ID
+				switch {
+				case first == ring[len(ring)-1].ID:
+					ring = append(ring, way[1:]...)
+				case last == ring[len(ring)-1].ID:
+					ring = append(ring, reversedNodes(way)[1:]...)
+				case last == ring[0].ID:
+					ring = append(append([]OSMNode(nil), way[:len(way)-1]...), ring...)
+				case first == ring[0].ID:
+					ring = append(reversedNodes(way)[:len(way)-1], ring...)
+				default:
+					continue
+				}
+				remaining = append(remaining[:i], remaining[i+1:]...)
+				joined = true
+				break
+			}
+			if !joined {
+				break
+			}
+		}
+
+		// A closed ring needs at least three distinct points plus the closing point
+		if ring[0].ID != ring[len(ring)-1].ID || len(ring) < 4 {
+			continue
+		}
+		points := make([][2]float64, len(ring))
+		for i, node := range ring {
+			points[i] = [2]float64{node.Lon, node.Lat}
+		}
+		rings = append(rings, points)
+	}
+	return rings
+}
+
+// reversedNodes returns a reversed copy of nodes
+func reversedNodes(nodes []OSMNode) []OSMNode {
+	reversed := make([]OSMNode, len(nodes))
+	for i, node := range nodes {
+		reversed[len(nodes)-1-i] = node
+	}
+	return reversed
+}
+
+// signedArea returns the ring's shoelace area in square degrees; positive
+// for counter-clockwise rings
+func signedArea(ring [][2]float64) float64 {
+	area := 0.0
+	for i := 0; i < len(ring)-1; i++ {
+		area += ring[i][0]*ring[i+1][1] - ring[i+1][0]*ring[i][1]
+	}
+	return area / 2
+}
+
+// orientRing returns ring wound counter-clockwise (ccw) or clockwise
+func orientRing(ring [][2]float64, ccw bool) [][2]float64 {
+	if (signedArea(ring) > 0) == ccw {
+		return ring
+	}
+	reversed := make([][2]float64, len(ring))
+	for i, point := range ring {
+		reversed[len(ring)-1-i] = point
+	}
+	return reversed
+}
+
+// pointInRing reports whether point lies inside ring, by ray casting
+func pointInRing(point [2]float64, ring [][2]float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > point[1]) != (yj > point[1]) &&
+			point[0] < (xj-xi)*(point[1]-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,148 @@
+#!/usr/bin/env python3
+"""
+Fallback Gazetteer Generator
+Writes search-core's static fallback gazetteer (graph/nepal_locations_fallback.json)
+from the most-searched locations in Elasticsearch. Run it against a synced index
+with search logging enabled, then rebuild search-core to embed the new file:
+
+    ELASTICSEARCH_URL=http://localhost:9200 python scripts/generate_fallback_gazetteer.py
+"""
+
+import os
+import json
+import logging
+from datetime import datetime, timedelta, timezone
+from typing import Dict, List
+
+from elasticsearch import Elasticsearch
+
+logging.basicConfig(
+    level=logging.INFO,
+    format='%(asctime)s - %(name)s - %(levelname)s - %(message)s'
+)
+logger = logging.getLogger(__name__)
+
+# Index that search-core's SearchLogger writes to (ENABLE_SEARCH_LOGGING=true)
+SEARCH_LOG_INDEX = 'nepal_search_logs'
+
+DEFAULT_OUTPUT = os.path.join(os.path.dirname(__file__), '..', 'search-core', 'graph', 'nepal_locations_fallback.json')
+
+# Document fields kept in the gazetteer: the names it matches on, the parent
+# filters it applies and what a search result shows
+GAZETTEER_FIELDS = (
+    'entity_type', 'name', 'name_ne', 'name_en', 'place_type', 'admin_level', 'location',
+    'municipality', 'municipality_ne', 'district', 'district_ne', 'province', 'province_ne',
+    'province_number', 'country', 'municipality_type', 'is_district_hq',
+)
+
+# Soft-deleted documents never go into the gazetteer
+DELETED = {'term': {'deleted': True}}
+
+
+class GazetteerGenerator:
+    """Picks the gazetteer's locations: every province, then the most frequent top
+    search results over the lookback window, topped up with the highest-boost admin
+    boundaries and places when the logs name fewer locations than the target size"""
+
+    def __init__(self, es: Elasticsearch, index: str, size: int, lookback_days: int):
+        self.es = es
+        self.index = index
+        self.size = size
+        self.lookback_days = lookback_days
+
+    def generate(self) -> List[Dict]:
+        """Return up to size gazetteer entries, most important first"""
+        entries = {}
+
+        def add(hits):
+            for hit in hits:
+                if len(entries) >= self.size:
+                    return
+                entries.setdefault(hit['_id'], self._entry(hit))
+
+        add(self._search({'bool': {'filter': [
+            {'term': {'entity_type': 'admin_boundary'}},
+            {'term': {'admin_level': 4}},
+        ]}}, 7))
+        provinces = len(entries)
+
+        add(self._most_searched())
+        searched = len(entries) - provinces
+
+        if len(entries) < self.size:
+            add(self._search({'bool': {
+                'filter': [{'terms': {'entity_type': ['admin_boundary', 'place']}}],
+                'must_not': [{'ids': {'values': list(entries)}}],
+            }}, self.size - len(entries)))
+
+        logger.info(
+            f"Gazetteer: {provinces} provinces, {searched} most-searched, "
+            f"{len(entries) - provinces - searched} by boost score"
+        )
+        return list(entries.values())
+
+    def _most_searched(self) -> List[Dict]:
+        """Fetch the most frequent top search results, most frequent first"""
+        if not self.es.indices.exists(index=SEARCH_LOG_INDEX):
+            logger.warning(f"{SEARCH_LOG_INDEX} does not exist (is ENABLE_SEARCH_LOGGING on?), using boost scores only")
+            return []
+
+        since = (datetime.now(timezone.utc) - timedelta(days=self.lookback_days)).isoformat()
+        buckets = self.es.search(
+            index=SEARCH_LOG_INDEX,
+            size=0,
+            query={'range': {'timestamp': {'gte': since}}},
+            aggs={'top_results': {'terms': {'field': 'top_result_id.keyword', 'size': self.size}}},
+        )['aggregations']['top_results']['buckets']
+        ids = [bucket['key'] for bucket in buckets]
+        if not ids:
+            return []
+
+        # mget keeps the log order; deleted and since-removed documents are dropped
+        docs = self.es.mget(index=self.index, ids=ids, source_includes=list(GAZETTEER_FIELDS) + ['deleted'])['docs']
+        return [doc for doc in docs if doc.get('found') and not doc['_source'].get('deleted')]
+
+    def _search(self, query: Dict, size: int) -> List[Dict]:
+        """Fetch the highest-boost documents matching query"""
+        return self.es.search(
+            index=self.index,
+            size=size,
+            query={'bool': {'filter': [query], 'must_not': [DELETED]}},
+            sort=[{'boost_score': 'desc'}, {'name.keyword': 'asc'}],
+            source_includes=list(GAZETTEER_FIELDS),
+        )['hits']['hits']
+
+    @staticmethod
+    def _entry(hit: Dict) -> Dict:
+        """Build a gazetteer entry in the shape of an indexed document"""
+        entry = {'id': hit['_id']}
+        for field in GAZETTEER_FIELDS:
+            value = hit['_source'].get(field)
+            if value not in (None, '', False):
+                entry[field] = value
+        return entry
+
+
+def write_gazetteer(entries: List[Dict], path: str):
+    """Write the entries one per line, matching the checked-in file's layout"""
+    lines = ',\n'.join('  ' + json.dumps(entry, ensure_ascii=False) for entry in entries)
+    with open(path, 'w', encoding='utf-8') as f:
+        f.write('[\n' + lines + '\n]\n')
+
+
+def main():
+    es = Elasticsearch([os.getenv('ELASTICSEARCH_URL', 'http://localhost:9200')])
+    generator = GazetteerGenerator(
+        es,
+        index=os.getenv('ES_INDEX', 'nepal_locations'),
+        size=int(os.getenv('GAZETTEER_SIZE', '1000')),
+        lookback_days=int(os.getenv('GAZETTEER_LOOKBACK_DAYS', '90')),
+    )
+    entries = generator.generate()
+    output = os.getenv('GAZETTEER_OUTPUT', DEFAULT_OUTPUT)
+    write_gazetteer(entries, output)
+    logger.info(f"Wrote {len(entries)} locations to {output}")
+
+
+if __name__ == '__main__':
+    main()
@@ -8,10 +8,18 @@ import os
 import sys
 import json
 import logging
-from typing import Dict, List, Optional
-from datetime import datetime
+import math
+import socket
+import ipaddress
+import urllib.error
+import urllib.parse
+import urllib.request
+from collections import Counter, deque
+from typing import Dict, List, Optional, Tuple
+from datetime import datetime, timezone
 
 import psycopg2
+import redis
 from psycopg2.extras import RealDictCursor
 from elasticsearch import Elasticsearch, helpers
 
@@ -22,6 +30,419 @@ logging.basicConfig(
 )
 logger = logging.getLogger(__name__)
 
+# Fixed mapping of Nepal province names (English and Nepali) to province numbers
+PROVINCE_NUMBERS = {
+    'koshi': 1, 'कोशी': 1,
+    'madhesh': 2, 'मधेश': 2,
+    'bagmati': 3, 'बागमती': 3,
+    'gandaki': 4, 'गण्डकी': 4,
+    'lumbini': 5, 'लुम्बिनी': 5,
+    'karnali': 6, 'कर्णाली': 6,
+    'sudurpashchim': 7, 'सुदूरपश्चिम': 7,
+}
+
+# Municipality populations, used to rank large municipalities above small ones
+POPULATION_FILE = os.path.join(os.path.dirname(os.path.abspath(__file__)), 'municipality_population.json')
+with open(POPULATION_FILE, encoding='utf-8') as f:
+    MUNICIPALITY_POPULATION = json.load(f)['populations']
+
+# Maximum boost added for the most populous municipality (log-scaled)
+POPULATION_WEIGHT = float(os.getenv('POPULATION_WEIGHT', '0.5'))
+
+# Municipality type suffixes stripped before the population lookup
+MUNICIPALITY_SUFFIXES = (
+    'sub-metropolitan city', 'metropolitan city', 'rural municipality',
+    'municipality', 'mahanagarpalika', 'upamahanagarpalika', 'nagarpalika', 'gaunpalika',
+)
+
+
+def municipality_population(name: Optional[str]) -> Optional[int]:
+    """Look up a municipality's population by name, ignoring type suffixes"""
+    if not name:
+        return None
+    key = name.strip().lower()
+    for suffix in MUNICIPALITY_SUFFIXES:
+        if key.endswith(suffix):
+            key = key[:-len(suffix)].strip()
+            break
+    return MUNICIPALITY_POPULATION.get(key)
+
+
+def population_weight(name: Optional[str]) -> float:
+    """Log-scaled boost in [0, POPULATION_WEIGHT] for a municipality's population"""
+    population = municipality_population(name)
+    if not population:
+        return 0.0
+    largest = max(MUNICIPALITY_POPULATION.values())
+    return POPULATION_WEIGHT * math.log10(population + 1) / math.log10(largest + 1)
+
+
+# Devanagari-to-Latin lookup for common Nepali phonemes
+DEVANAGARI_CONSONANTS = {
+    'क': 'k', 'ख': 'kh', 'ग': 'g', 'घ': 'gh', 'ङ': 'ng',
+    'च': 'ch', 'छ': 'chh', 'ज': 'j', 'झ': 'jh', 'ञ': 'ny',
+    'ट': 't', 'ठ': 'th', 'ड': 'd', 'ढ': 'dh', 'ण': 'n',
+    'त': 't', 'थ': 'th', 'द': 'd', 'ध': 'dh', 'न': 'n',
+    'प': 'p', 'फ': 'ph', 'ब': 'b', 'भ': 'bh', 'म': 'm',
+    'य': 'y', 'र': 'r', 'ल': 'l', 'व': 'w', 'श': 'sh',
+    'ष': 'sh', 'स': 's', 'ह': 'h',
+}
+DEVANAGARI_VOWELS = {
+    'अ': 'a', 'आ': 'aa', 'इ': 'i', 'ई': 'i', 'उ': 'u', 'ऊ': 'u',
+    'ऋ': 'ri', 'ए': 'e', 'ऐ': 'ai', 'ओ': 'o', 'औ': 'au',
+}
+DEVANAGARI_MATRAS = {
+    'ा': 'a', 'ि': 'i', 'ी': 'i', 'ु': 'u', 'ू': 'u',
+    'ृ': 'ri', 'े': 'e', 'ै': 'ai', 'ो': 'o', 'ौ': 'au',
+}
+DEVANAGARI_SIGNS = {'ं': 'n', 'ँ': 'n', 'ः': 'h'}
+DEVANAGARI_VIRAMA = '्'
+DEVANAGARI_DIGITS = {chr(0x0966 + i): str(i) for i in range(10)}
+
+
+# Phonetically similar Devanagari characters mapped to one canonical form, so
+# commonly confused sounds (e.g. क/ग) match. Mirrors nepaliPhonemes in
+# search-core/internal/normalize/normalize.go, which normalizes queries.
+NEPALI_PHONEMES = str.maketrans({
+    'ख': 'क', 'ग': 'क', 'घ': 'क',
+    'छ': 'च', 'ज': 'च', 'झ': 'च',
+    'ठ': 'ट', 'ड': 'ट', 'ढ': 'ट',
+    'थ': 'त', 'द': 'त', 'ध': 'त',
+    'फ': 'प', 'ब': 'प', 'भ': 'प', 'व': 'प',
+    'ङ': 'न', 'ञ': 'न', 'ण': 'न',
+    'श': 'स', 'ष': 'स',
+    'ई': 'इ', 'ऊ': 'उ', 'ी': 'ि', 'ू': 'ु',
+    'ँ': 'ं',
+})
+
+
+def normalize_nepali_phonemes(text: str) -> str:
+    """Map phonetically similar Devanagari characters to canonical forms"""
+    return text.translate(NEPALI_PHONEMES)
+
+
+def has_devanagari(text: Optional[str]) -> bool:
+    """Check whether text contains any Devanagari characters"""
+    return bool(text) and any('\u0900' <= ch <= '\u097f' for ch in text)
+
+
+def transliterate_devanagari(text: str) -> str:
+    """Romanize Devanagari text, e.g. 'ललितपुर' -> 'Lalitpur'"""
+    words = []
+    for word in text.split():
+        out = []
+        for i, ch in enumerate(word):
+            nxt = word[i + 1] if i + 1 < len(word) else ''
+            if ch in DEVANAGARI_CONSONANTS:
+                out.append(DEVANAGARI_CONSONANTS[ch])
+                # Inherent 'a' unless a vowel sign or virama follows; Nepali drops it word-finally
+                if nxt and nxt not in DEVANAGARI_MATRAS and nxt != DEVANAGARI_VIRAMA and nxt not in DEVANAGARI_SIGNS:
+                    out.append('a')
+                elif nxt in DEVANAGARI_SIGNS:
+                    out.append('a')
+            elif ch in DEVANAGARI_VOWELS:
+                out.append(DEVANAGARI_VOWELS[ch])
+            elif ch in DEVANAGARI_MATRAS:
+                out.append(DEVANAGARI_MATRAS[ch])
+            elif ch in DEVANAGARI_SIGNS:
+                out.append(DEVANAGARI_SIGNS[ch])
+            elif ch in DEVANAGARI_DIGITS:
+                out.append(DEVANAGARI_DIGITS[ch])
+            elif ch == DEVANAGARI_VIRAMA or '\u0900' <= ch <= '\u097f':
+                continue
+            else:
+                out.append(ch)
+        words.append(''.join(out).capitalize())
+    return ' '.join(words)
+
+
+# OSM highway classes indexed as 'highway' entities (e.g. Tribhuvan Highway);
+# other named roads are indexed as 'road'
+MAJOR_HIGHWAY_CLASSES = ('motorway', 'trunk', 'primary', 'secondary')
+
+
+# OSM tag keys whose features are indexed as 'amenity' entities, in priority
+# order for picking the place_type (e.g. amenity=hospital, shop=supermarket).
+# The osm2pgsql default style stores each as a column left out of tags, so the
+# sync queries COALESCE the column with the hstore key.
+AMENITY_TAG_KEYS = ('amenity', 'tourism', 'shop', 'leisure')
+
+
+# Admin levels indexed for Nepal: province, district, municipality, ward
+VALID_ADMIN_LEVELS = {4, 6, 7, 9}
+
+
+# Local government types, matched against the designation tag or the English name
+MUNICIPALITY_TYPE_KEYWORDS = (
+    ('sub-metropolitan', 'sub_metropolitan'),
+    ('sub metropolitan', 'sub_metropolitan'),
+    ('metropolitan', 'metropolitan'),
+    ('rural municipality', 'rural_municipality'),
+    ('gaunpalika', 'rural_municipality'),
+    ('municipality', 'municipality'),
+    ('nagarpalika', 'municipality'),
+)
+
+# Fallback from the place tag when neither designation nor name names the type
+MUNICIPALITY_TYPE_BY_PLACE = {
+    'city': 'metropolitan',
+    'town': 'municipality',
+    'village': 'rural_municipality',
+}
+
+
+def municipality_type(name: Optional[str], tags: Dict) -> Optional[str]:
+    """Derive metropolitan, sub_metropolitan, municipality or rural_municipality from OSM tags"""
+    for text in (tags.get('designation'), tags.get('name:en'), name):
+        if not text:
+            continue
+        text = str(text).lower().replace('_', ' ')
+        for keyword, municipality_kind in MUNICIPALITY_TYPE_KEYWORDS:
+            if keyword in text:
+                return municipality_kind
+    return MUNICIPALITY_TYPE_BY_PLACE.get(tags.get('place'))
+
+
+def osm_last_modified(tags: Dict) -> Optional[str]:
+    """Return the element's last OSM edit time from its osm:timestamp metadata
+    (osm2pgsql --extra-attributes stores it as osm_timestamp), or None"""
+    value = tags.get('osm:timestamp') or tags.get('osm_timestamp')
+    if not value:
+        return None
+    try:
+        return datetime.fromisoformat(str(value).replace('Z', '+00:00')).isoformat()
+    except ValueError:
+        return None
+
+
+def validate_feature(row: Dict, entity_type: str) -> List[str]:
+    """Return validation errors for an OSM feature row (empty list if valid)"""
+    errors = []
+    
+    name = row.get('name')
+    if not name or not str(name).strip():
+        errors.append('missing name')
+    
+    lat, lon = row.get('lat'), row.get('lon')
+    if lat is not None and lon is not None and float(lat) == 0 and float(lon) == 0:
+        errors.append('zero coordinates')
+    elif row.get('centroid') in ('POINT(0 0)', 'POINT EMPTY'):
+        errors.append('zero coordinates')
+    
+    admin_level = row.get('admin_level')
+    if entity_type == 'admin_boundary' and admin_level not in VALID_ADMIN_LEVELS:
+        errors.append('invalid admin_level')
+    elif admin_level is not None and not 1 <= int(admin_level) <= 11:
+        errors.append('invalid admin_level')
+    
+    return errors
+
+
+# Partial reindex jobs queued by search-core's reindexFromOSM mutation
+REINDEX_JOBS_INDEX = 'nepal_reindex_jobs'
+
+# Location alerts stored by search-core's createLocationAlert mutation as percolator queries
+ALERTS_INDEX = 'nepal_location_alerts'
+
+
+# Large derived fields left out of published messages and alert webhooks
+UNPUBLISHED_FIELDS = ('boundary', 'location_vector')
+
+# Host names that only resolve inside the deployment, never valid webhook targets
+INTERNAL_HOST_SUFFIXES = ('.local', '.localhost', '.internal', '.lan', '.home.arpa')
+
+
+def webhook_url_allowed(url: str) -> bool:
+    """Whether a webhook URL is an http(s) URL on a public host. Single-label and
+    internal names are rejected, as are hosts resolving to loopback, private,
+    link-local or other non-public addresses."""
+    parsed = urllib.parse.urlparse(url)
+    host = (parsed.hostname or '').rstrip('.').lower()
+    if parsed.scheme not in ('http', 'https') or not host:
+        return False
+    try:
+        ipaddress.ip_address(host)
+    except ValueError:
+        if host == 'localhost' or '.' not in host or host.endswith(INTERNAL_HOST_SUFFIXES):
+            return False
+    try:
+        port = parsed.port
+        infos = socket.getaddrinfo(host, port, proto=socket.IPPROTO_TCP)
+    except (ValueError, OSError):
+        return False
+    # Scoped IPv6 addresses carry a %zone suffix
+    addresses = {ipaddress.ip_address(info[4][0].split('%')[0]) for info in infos}
+    return bool(addresses) and all(address.is_global for address in addresses)
+
+
+class NoRedirectHandler(urllib.request.HTTPRedirectHandler):
+    """Fails redirects instead of following them, so an allowed webhook host
+    cannot forward the request to an internal one"""
+    
+    def redirect_request(self, req, fp, code, msg, headers, newurl):
+        return None
+
+
+class AlertNotifier:
+    """Percolates new location documents against the alerts stored by search-core's
+    createLocationAlert mutation and POSTs each match to the alert's webhook. A document
+    is new when its ID was not in the index before the run (previous_ids)."""
+    
+    BATCH_SIZE = 100
+    # Cap on the alerts returned for one percolated batch
+    MAX_MATCHES = 1000
+    
+    def __init__(self, es: Elasticsearch, timeout: float):
+        self.es = es
+        self.timeout = timeout
+        self.opener = urllib.request.build_opener(NoRedirectHandler)
+        self.previous_ids = set()
+        self.pending = []
+        
+    def add(self, doc: Dict):
+        """Queue an indexed document if it is new, percolating once a batch is full"""
+        if doc['_id'] in self.previous_ids:
+            return
+        source = {k: v for k, v in doc['_source'].items() if k not in UNPUBLISHED_FIELDS}
+        self.pending.append((doc['_id'], source))
+        if len(self.pending) >= self.BATCH_SIZE:
+            self.flush()
+            
+    def flush(self):
+        """Percolate the queued documents; failures are logged, never failing the sync"""
+        batch, self.pending = self.pending, []
+        if not batch:
+            return
+        try:
+            matches = self.es.search(
+                index=ALERTS_INDEX,
+                query={'percolate': {'field': 'query', 'documents': [source for _, source in batch]}},
+                source=['webhook_url'],
+                size=self.MAX_MATCHES,
+                ignore_unavailable=True,
+            )['hits']['hits']
+        except Exception as e:
+            logger.warning(f"Location alert percolation failed: {e}")
+            return
+        
+        sent = 0
+        for alert in matches:
+            url = alert['_source']['webhook_url']
+            for slot in alert.get('fields', {}).get('_percolator_document_slot', []):
+                doc_id, source = batch[slot]
+                payload = {'alert_id': alert['_id'], 'document_id': doc_id, 'document': source}
+                if self._send(url, payload):
+                    sent += 1
+        logger.info(f"Location alerts: percolated {len(batch)} new document(s), {sent} webhook(s) sent")
+                    
+    def _send(self, url: str, payload: Dict) -> bool:
+        """POST a webhook, rechecking the host so a DNS change cannot point it inside the network"""
+        if not webhook_url_allowed(url):
+            logger.warning(f"Webhook for alert {payload['alert_id']} skipped: {url} is not a public host")
+            return False
+        request = urllib.request.Request(
+            url,
+            data=json.dumps(payload, default=str).encode('utf-8'),
+            headers={'Content-Type': 'application/json'},
+            method='POST',
+        )
+        try:
+            with self.opener.open(request, timeout=self.timeout) as response:
+                response.read()
+        except (urllib.error.URLError, OSError) as e:
+            logger.warning(f"Webhook for alert {payload['alert_id']} failed: {e}")
+            return False
+        return True
+
+
+class BulkIndexer:
+    """Bulk-indexes documents into Elasticsearch. With an AlertNotifier, indexed
+    documents are passed on to be percolated against location alerts."""
+    
+    def __init__(self, es: Elasticsearch, alerts: Optional[AlertNotifier] = None):
+        self.es = es
+        self.alerts = alerts
+        
+    def index(self, docs):
+        """Index the documents, returning (succeeded, failed) counts"""
+        # streaming_bulk reports results in request order, so results pair up with pending docs
+        pending = deque()
+        
+        def track(docs):
+            for doc in docs:
+                pending.append(doc)
+                yield doc
+        
+        succeeded = failed = 0
+        for ok, _ in helpers.streaming_bulk(self.es, track(docs), raise_on_error=False):
+            doc = pending.popleft()
+            if not ok:
+                failed += 1
+                continue
+            succeeded += 1
+            self._indexed(doc)
+        if self.alerts:
+            self.alerts.flush()
+        return succeeded, failed
+        
+    def _indexed(self, doc: Dict):
+        """Handle a successfully indexed document"""
+        # Only location documents (which have an entity type) can match alerts
+        if self.alerts and doc['_source'].get('entity_type'):
+            self.alerts.add(doc)
+
+
+class PublishingIndexer(BulkIndexer):
+    """Bulk-indexes documents and publishes each indexed location to Redis, on the
+    nepal_locations:{municipality} channel read by search-core's locationUpdated subscription"""
+    
+    def __init__(self, es: Elasticsearch, redis_client, alerts: Optional[AlertNotifier] = None):
+        super().__init__(es, alerts)
+        self.redis = redis_client
+        
+    def _indexed(self, doc: Dict):
+        super()._indexed(doc)
+        self._publish(doc)
+        
+    def _publish(self, doc: Dict):
+        """Publish an indexed location document; publish failures never fail the sync"""
+        source = doc['_source']
+        municipality = source.get('municipality')
+        # Only location documents (which have an entity type) in a municipality have subscribers
+        if not municipality or not source.get('entity_type'):
+            return
+        
+        message = {
+            '_id': doc['_id'],
+            '_source': {k: v for k, v in source.items() if k not in UNPUBLISHED_FIELDS},
+        }
+        try:
+            self.redis.publish(f"nepal_locations:{municipality.strip().lower()}", json.dumps(message, default=str))
+        except redis.RedisError as e:
+            logger.warning(f"Failed to publish update for {doc['_id']}: {e}")
+
+
+class NullIndexer:
+    """Discards documents for dry runs, counting them and keeping a few samples per entity type"""
+    
+    SAMPLES_PER_TYPE = 5
+    
+    def __init__(self):
+        self.counts = Counter()
+        self.samples = {}
+        
+    def index(self, docs):
+        """Consume the documents without indexing them, returning (succeeded, failed) counts"""
+        indexed = 0
+        for doc in docs:
+            entity_type = doc['_source'].get('entity_type') or doc['_index']
+            self.counts[entity_type] += 1
+            samples = self.samples.setdefault(entity_type, [])
+            if len(samples) < self.SAMPLES_PER_TYPE:
+                samples.append(doc)
+            indexed += 1
+        return indexed, 0
+
 
 class LocationSyncer:
     """Syncs location data from PostgreSQL to Elasticsearch"""
@@ -29,15 +450,54 @@ class LocationSyncer:
     def __init__(self):
         self.es_url = os.getenv('ELASTICSEARCH_URL', 'http://localhost:9200')
         self.es_index = os.getenv('ES_INDEX', 'nepal_locations')
+        self.boundaries_index = os.getenv('ES_BOUNDARIES_INDEX', 'nepal_boundaries')
+        self.province_geojson_url = os.getenv('PROVINCE_GEOJSON_URL', '')
+        # GeoJSON feature properties holding the province name (first present wins)
+        self.province_name_properties = [
+            p.strip() for p in os.getenv('PROVINCE_GEOJSON_NAME_PROPERTIES', 'name,PROVINCE,Province,PR_NAME').split(',')
+            if p.strip()
+        ]
+        self.ward_geojson_url = os.getenv('WARD_GEOJSON_URL', '')
+        # GeoJSON feature properties holding the municipality name and ward number (first present wins)
+        self.ward_municipality_properties = [
+            p.strip() for p in os.getenv('WARD_GEOJSON_MUNICIPALITY_PROPERTIES', 'municipality,GaPa_NaPa,LOCAL').split(',')
+            if p.strip()
+        ]
+        self.ward_number_properties = [
+            p.strip() for p in os.getenv('WARD_GEOJSON_WARD_PROPERTIES', 'ward,NEW_WARD_N,WARD').split(',')
+            if p.strip()
+        ]
         self.db_host = os.getenv('POSTGRES_HOST', 'localhost')
         self.db_port = os.getenv('POSTGRES_PORT', '5433')
         self.db_name = os.getenv('POSTGRES_DB', 'nepal_location_pg')
         self.db_user = os.getenv('POSTGRES_USER', 'osm_user')
         self.db_pass = os.getenv('POSTGRES_PASSWORD', 'osm_secret_password')
         self.force_recreate = os.getenv('FORCE_RECREATE', 'false').lower() == 'true'
+        self.num_shards = int(os.getenv('ES_NUM_SHARDS', '3'))
+        self.num_replicas = int(os.getenv('ES_NUM_REPLICAS', '1'))
+        # Changing this requires rebuilding the index (FORCE_RECREATE=true)
+        self.routing_enabled = os.getenv('ENABLE_ROUTING_OPTIMIZATION', 'false').lower() == 'true'
+        # Validate and report without writing to Elasticsearch
+        self.dry_run = os.getenv('DRY_RUN', 'false').lower() == 'true'
+        self.skipped = Counter()
+        self.invalid_by_type = Counter()
+        self.municipality_codes = {}
+        self.district_headquarters = set()
         
         # Initialize connections
         self.es = Elasticsearch([self.es_url])
+        alerts = None
+        if os.getenv('ENABLE_LOCATION_ALERTS', 'false').lower() == 'true':
+            alerts = AlertNotifier(self.es, float(os.getenv('ALERT_WEBHOOK_TIMEOUT_SECONDS', '10')))
+        if self.dry_run:
+            self.indexer = NullIndexer()
+        elif os.getenv('REDIS_URL'):
+            # Publish indexed locations for search-core's locationUpdated subscription
+            self.indexer = PublishingIndexer(self.es, redis.Redis.from_url(os.getenv('REDIS_URL')), alerts)
+        else:
+            self.indexer = BulkIndexer(self.es, alerts)
+        # Set while processing a partial reindex job; documents outside it are not indexed
+        self.reindex_bbox = None
         self.conn = None
         
     def connect_db(self):
@@ -77,6 +537,12 @@ class LocationSyncer:
             logger.warning(f"Mapping file not found at {mapping_path}, using default")
             mapping = self._get_default_mapping()
         
+        # Shard/replica counts come from env (use ES_NUM_REPLICAS=0 on single-node clusters)
+        settings = mapping.setdefault('settings', {})
+        settings['number_of_shards'] = self.num_shards
+        settings['number_of_replicas'] = self.num_replicas
+        logger.info(f"Index settings: {self.num_shards} shard(s), {self.num_replicas} replica(s)")
+        
         if self.es.indices.exists(index=self.es_index):
             if self.force_recreate:
                 logger.info(f"Index {self.es_index} already exists, deleting (FORCE_RECREATE=true)...")
@@ -89,6 +555,161 @@ class LocationSyncer:
             self.es.indices.create(index=self.es_index, body=mapping)
             logger.info(f"Created index: {self.es_index}")
         
+        self._create_alerts_index(mapping)
+        
+    def load_previous_ids(self):
+        """Record the IDs of the documents left by the previous sync, so location alerts
+        fire only for documents new since then. Must run before create_index, which
+        drops the index with FORCE_RECREATE. With no previous documents (a first sync,
+        or an index left empty by a failed run) every document would count as new,
+        so alerts are disabled for the run."""
+        alerts = getattr(self.indexer, 'alerts', None)
+        if not alerts:
+            return
+        
+        count = 0
+        if self.es.indices.exists(index=self.es_index):
+            count = self.es.count(index=self.es_index)['count']
+        if count == 0:
+            logger.info(f"Location alerts disabled: {self.es_index} holds no documents from a previous sync")
+            self.indexer.alerts = None
+            return
+        
+        alerts.previous_ids = {
+            hit['_id'] for hit in helpers.scan(self.es, index=self.es_index, query={'query': {'match_all': {}}, '_source': False})
+        }
+        logger.info(f"Location alerts: comparing against {len(alerts.previous_ids)} previously synced document(s)")
+            
+    def _create_alerts_index(self, mapping: Dict):
+        """Create the location alerts index if missing. Percolator queries need the
+        location fields mapped, so it reuses the locations mapping and analyzers.
+        Existing alerts are never deleted, even with FORCE_RECREATE."""
+        if self.es.indices.exists(index=ALERTS_INDEX):
+            return
+        
+        properties = dict(mapping.get('mappings', {}).get('properties', {}))
+        properties.update({
+            'query': {'type': 'percolator'},
+            'webhook_url': {'type': 'keyword'},
+            'search_query': {'type': 'keyword'},
+            'created_at': {'type': 'date'},
+        })
+        self.es.indices.create(index=ALERTS_INDEX, body={
+            'settings': mapping.get('settings', {}),
+            'mappings': {'properties': properties},
+        })
+        logger.info(f"Created index: {ALERTS_INDEX}")
+        
+    def _load_mapping(self, filename: str) -> Optional[Dict]:
+        """Load an index mapping from the Docker or local mappings directory"""
+        for directory in ('/app/mappings', os.path.join(os.path.dirname(__file__), '..', 'elasticsearch', 'mappings')):
+            path = os.path.join(directory, filename)
+            if os.path.exists(path):
+                with open(path, 'r') as f:
+                    return json.load(f)
+        return None
+        
+    def sync_province_boundaries(self) -> int:
+        """Import official province polygons from PROVINCE_GEOJSON_URL into the boundaries index"""
+        if not self.province_geojson_url:
+            logger.info("PROVINCE_GEOJSON_URL not set, skipping province boundary import")
+            return 0
+        
+        logger.info(f"Fetching province boundaries from {self.province_geojson_url}")
+        with urllib.request.urlopen(self.province_geojson_url, timeout=60) as response:
+            collection = json.load(response)
+        
+        mapping = self._boundaries_mapping()
+        
+        # Boundaries are small and fully replaced on every import
+        if not self.dry_run:
+            if self.es.indices.exists(index=self.boundaries_index):
+                self.es.indices.delete(index=self.boundaries_index)
+            self.es.indices.create(index=self.boundaries_index, body=mapping)
+        
+        def generate_docs():
+            for i, feature in enumerate(collection.get('features', [])):
+                properties = feature.get('properties') or {}
+                geometry = feature.get('geometry')
+                name = next((str(properties[key]) for key in self.province_name_properties if properties.get(key)), None)
+                if not geometry or not name:
+                    self.skipped.update(['province boundary without name or geometry'])
+                    self.invalid_by_type[self.boundaries_index] += 1
+                    continue
+                
+                yield {
+                    '_index': self.boundaries_index,
+                    '_id': f"province_{i}",
+                    '_source': {
+                        'name': name,
+                        'name_ne': properties.get('name:ne') or properties.get('name_ne'),
+                        'province_number': self._province_number(name),
+                        'source': self.province_geojson_url,
+                        'boundary': geometry,
+                        'last_updated': datetime.now(timezone.utc).isoformat(),
+                    }
+                }
+        
+        success, failed = self.indexer.index(generate_docs())
+        logger.info(f"Imported {success} province boundaries ({failed} failed)")
+        return success
+        
+    def sync_ward_boundaries(self) -> int:
+        """Import official ward polygons from WARD_GEOJSON_URL into the boundaries index.
+        Runs after the province import, which recreates the index."""
+        if not self.ward_geojson_url:
+            logger.info("WARD_GEOJSON_URL not set, skipping ward boundary import")
+            return 0
+        
+        logger.info(f"Fetching ward boundaries from {self.ward_geojson_url}")
+        with urllib.request.urlopen(self.ward_geojson_url, timeout=120) as response:
+            collection = json.load(response)
+        
+        if not self.dry_run and not self.es.indices.exists(index=self.boundaries_index):
+            self.es.indices.create(index=self.boundaries_index, body=self._boundaries_mapping())
+        
+        def generate_docs():
+            for feature in collection.get('features', []):
+                properties = feature.get('properties') or {}
+                geometry = feature.get('geometry')
+                municipality = next((str(properties[key]).strip() for key in self.ward_municipality_properties if properties.get(key)), None)
+                ward = next((properties[key] for key in self.ward_number_properties if properties.get(key)), None)
+                try:
+                    ward = int(ward) if ward is not None else None
+                except (TypeError, ValueError):
+                    ward = None
+                if not geometry or not municipality or not ward:
+                    self.skipped.update(['ward boundary without municipality, ward or geometry'])
+                    self.invalid_by_type[self.boundaries_index] += 1
+                    continue
+                
+                yield {
+                    '_index': self.boundaries_index,
+                    '_id': f"ward_{municipality.lower().replace(' ', '_')}_{ward}",
+                    '_source': {
+                        'name': f"{municipality} Ward {ward}",
+                        'municipality': municipality,
+                        'ward': ward,
+                        'source': self.ward_geojson_url,
+                        'boundary': geometry,
+                        'last_updated': datetime.now(timezone.utc).isoformat(),
+                    }
+                }
+        
+        success, failed = self.indexer.index(generate_docs())
+        logger.info(f"Imported {success} ward boundaries ({failed} failed)")
+        return success
+        
+    def _boundaries_mapping(self) -> Dict:
+        """Boundaries index mapping, with a single shard since the index is small"""
+        mapping = self._load_mapping('nepal_boundaries.json') or {
+            'mappings': {'properties': {'boundary': {'type': 'geo_shape'}}}
+        }
+        settings = mapping.setdefault('settings', {})
+        settings['number_of_shards'] = 1
+        settings['number_of_replicas'] = self.num_replicas
+        return mapping
+        
     def _get_default_mapping(self) -> Dict:
         """Fallback default mapping"""
         return {
@@ -117,6 +738,8 @@ class LocationSyncer:
         query = """
         SELECT 
             'place_' || id::text as doc_id,
+            ABS(osm_id) as osm_id,  -- osm2pgsql stores relation IDs as negatives
+            CASE WHEN osm_id < 0 THEN 'relation' ELSE osm_type END as osm_type,
             'place' as entity_type,
             name,
             name_ne,
@@ -138,6 +761,9 @@ class LocationSyncer:
             
             def generate_docs():
                 for row in cur:
+                    if self._skip_invalid(row, 'place'):
+                        continue
+                    
                     # Extract English names from tags if available
                     tags = row.get('tags')
                     if isinstance(tags, str):
@@ -150,20 +776,24 @@ class LocationSyncer:
                     elif tags is None:
                         tags = {}
                     
-                    name_en = tags.get('name:en', row['name'])
+                    name_ne, name_en = self._localized_names(row, tags)
                     
                     # Boost score based on entity type
-                    boost = self._calculate_boost('place', row.get('place_type'))
+                    boost = self._calculate_boost('place', row.get('place_type'), row.get('municipality'))
                     
                     doc = {
                         '_index': self.es_index,
                         '_id': row['doc_id'],
                         '_source': {
                             'id': row['doc_id'],
+                            'osm_id': row.get('osm_id'),
+                            'osm_type': row.get('osm_type'),
                             'entity_type': row['entity_type'],
                             'name': row['name'],
-                            'name_ne': row.get('name_ne'),
+                            'name_ne': name_ne,
                             'name_en': name_en,
+                            'name_romanized': self._romanized_name(row, tags),
+                            'osm_last_modified': osm_last_modified(tags),
                             'place_type': row.get('place_type'),
                             'admin_level': row.get('admin_level'),
                             'location': {
@@ -177,14 +807,18 @@ class LocationSyncer:
                             'district_ne': row.get('district'),
                             'province': row.get('province'),
                             'province_ne': row.get('province'),
+                            'province_number': self._province_number(row.get('province')),
                             'country': 'Nepal',
+                            'postal_code': tags.get('addr:postcode'),
+                            'is_district_hq': self._is_district_hq(row, tags),
+                            'last_updated': datetime.now(timezone.utc).isoformat(),
                             'boost_score': boost,
                             'search_text': self._build_search_text(row)
                         }
                     }
                     yield doc
                     
-            success, failed = helpers.bulk(self.es, generate_docs(), raise_on_error=False)
+            success, failed = self.indexer.index(self._prepare_docs(generate_docs()))
             logger.info(f"Synced {success} places ({failed} failed)")
             return success
             
@@ -193,6 +827,8 @@ class LocationSyncer:
         query = """
         SELECT 
             'admin_' || id::text as doc_id,
+            ABS(osm_id) as osm_id,
+            CASE WHEN osm_id < 0 THEN 'relation' ELSE 'way' END as osm_type,
             'admin_boundary' as entity_type,
             name,
             name_ne,
@@ -201,6 +837,11 @@ class LocationSyncer:
             ST_Y(ST_Transform(centroid, 4326)) as lat,
             ST_X(ST_Transform(centroid, 4326)) as lon,
             ST_AsText(ST_Transform(centroid, 4326)) as centroid,
+            -- Province polygons for containment checks, municipality polygons
+            -- for the neighbors lookup
+            CASE WHEN admin_level IN (4, 7)
+                THEN ST_AsGeoJSON(ST_SimplifyPreserveTopology(ST_Transform(geom, 4326), 0.001))
+            END as boundary,
             tags
         FROM normalized.admin_boundaries
         WHERE name IS NOT NULL
@@ -212,6 +853,9 @@ class LocationSyncer:
             
             def generate_docs():
                 for row in cur:
+                    if self._skip_invalid(row, 'admin_boundary'):
+                        continue
+                    
                     # Parse tags from HSTORE string format
                     tags = row.get('tags')
                     if isinstance(tags, str):
@@ -222,21 +866,25 @@ class LocationSyncer:
                             tags = {}
                     elif tags is None:
                         tags = {}
-                    name_en = tags.get('name:en', row['name'])
+                    name_ne, name_en = self._localized_names(row, tags)
                     
                     # Determine hierarchy based on admin_level
                     hierarchy = self._build_admin_hierarchy(row)
-                    boost = self._calculate_boost('admin_boundary', row.get('admin_level'))
+                    boost = self._calculate_boost('admin_boundary', row.get('admin_level'), hierarchy.get('municipality'))
                     
                     doc = {
                         '_index': self.es_index,
                         '_id': row['doc_id'],
                         '_source': {
                             'id': row['doc_id'],
+                            'osm_id': row.get('osm_id'),
+                            'osm_type': row.get('osm_type'),
                             'entity_type': row['entity_type'],
                             'name': row['name'],
-                            'name_ne': row.get('name_ne'),
+                            'name_ne': name_ne,
                             'name_en': name_en,
+                            'name_romanized': self._romanized_name(row, tags),
+                            'osm_last_modified': osm_last_modified(tags),
                             'admin_level': row.get('admin_level'),
                             'location': {
                                 'lat': row['lat'],
@@ -249,14 +897,23 @@ class LocationSyncer:
                             'district_ne': hierarchy.get('district_ne'),
                             'province': hierarchy.get('province'),
                             'province_ne': hierarchy.get('province_ne'),
+                            'province_number': self._province_number(
+                                hierarchy.get('province'),
+                                tags if row.get('admin_level') == 4 else None
+                            ),
+                            'municipality_type': municipality_type(row['name'], tags) if row.get('admin_level') == 7 else None,
                             'country': 'Nepal',
+                            'postal_code': tags.get('addr:postcode'),
+                            'last_updated': datetime.now(timezone.utc).isoformat(),
                             'boost_score': boost,
                             'search_text': self._build_search_text(row)
                         }
                     }
+                    if row.get('boundary'):
+                        doc['_source']['boundary'] = json.loads(row['boundary'])
                     yield doc
                     
-            success, failed = helpers.bulk(self.es, generate_docs(), raise_on_error=False)
+            success, failed = self.indexer.index(self._prepare_docs(generate_docs()))
             logger.info(f"Synced {success} admin boundaries ({failed} failed)")
             return success
             
@@ -265,6 +922,8 @@ class LocationSyncer:
         query = """
         SELECT 
             'poi_' || id::text as doc_id,
+            ABS(osm_id) as osm_id,
+            'node' as osm_type,  -- normalized.poi is built from planet_osm_point
             'poi' as entity_type,
             name,
             ST_Y(ST_Transform(geom, 4326)) as lat,
@@ -273,6 +932,7 @@ class LocationSyncer:
             tags
         FROM normalized.poi
         WHERE name IS NOT NULL
+          AND COALESCE(amenity, tourism, shop, leisure) IS NULL  -- Amenities are synced by sync_amenities
         LIMIT 50000  -- Limit POI to top 50k to keep index size reasonable
         """
         
@@ -281,6 +941,9 @@ class LocationSyncer:
             
             def generate_docs():
                 for row in cur:
+                    if self._skip_invalid(row, 'poi'):
+                        continue
+                    
                     # Parse tags from HSTORE string format
                     tags = row.get('tags')
                     if isinstance(tags, str):
@@ -291,7 +954,7 @@ class LocationSyncer:
                             tags = {}
                     elif tags is None:
                         tags = {}
-                    name_en = tags.get('name:en', row['name'])
+                    name_ne, name_en = self._localized_names(row, tags)
                     
                     # Get hierarchy via spatial lookup
                     hierarchy = {
@@ -318,9 +981,14 @@ class LocationSyncer:
                         '_id': row['doc_id'],
                         '_source': {
                             'id': row['doc_id'],
+                            'osm_id': row.get('osm_id'),
+                            'osm_type': row.get('osm_type'),
                             'entity_type': row['entity_type'],
                             'name': row['name'],
+                            'name_ne': name_ne,
                             'name_en': name_en,
+                            'name_romanized': self._romanized_name(row, tags),
+                            'osm_last_modified': osm_last_modified(tags),
                             'location': {
                                 'lat': row['lat'],
                                 'lon': row['lon']
@@ -331,15 +999,18 @@ class LocationSyncer:
                             'district_ne': hierarchy.get('district_ne'),
                             'province': hierarchy.get('province'),
                             'province_ne': hierarchy.get('province_ne'),
+                            'province_number': self._province_number(hierarchy.get('province')),
                             'country': 'Nepal',
-                            'boost_score': 0.5,  # Lower priority for POI
+                            'postal_code': tags.get('addr:postcode'),
+                            'last_updated': datetime.now(timezone.utc).isoformat(),
+                            'boost_score': self._calculate_boost('poi', None, hierarchy.get('municipality')),
                             'tags': tags,
                             'search_text': row['name']
                         }
                     }
                     yield doc
                     
-            success, failed = helpers.bulk(self.es, generate_docs(), raise_on_error=False)
+            success, failed = self.indexer.index(self._prepare_docs(generate_docs()))
             logger.info(f"Synced {success} POI ({failed} failed)")
             return success
             
@@ -348,20 +1019,26 @@ class LocationSyncer:
         query = """
         SELECT 
             'road_' || id::text as doc_id,
+            ABS(osm_id) as osm_id,
+            CASE WHEN osm_id < 0 THEN 'relation' ELSE 'way' END as osm_type,
             'road' as entity_type,
             name,
             ST_AsText(ST_Transform(ST_Centroid(geom), 4326)) as centroid,
             tags
         FROM normalized.named_roads
         WHERE name IS NOT NULL
+          AND COALESCE(highway, '') NOT IN %s  -- Major highways are synced by sync_highways
         LIMIT 10000  -- Limit roads
         """
         
         with self.conn.cursor(cursor_factory=RealDictCursor) as cur:
-            cur.execute(query)
+            cur.execute(query, (MAJOR_HIGHWAY_CLASSES,))
             
             def generate_docs():
                 for row in cur:
+                    if self._skip_invalid(row, 'road'):
+                        continue
+                    
                     # Parse tags from HSTORE string format
                     tags = row.get('tags')
                     if isinstance(tags, str):
@@ -372,7 +1049,7 @@ class LocationSyncer:
                             tags = {}
                     elif tags is None:
                         tags = {}
-                    name_en = tags.get('name:en', row['name'])
+                    name_ne, name_en = self._localized_names(row, tags)
                     
                     # Get hierarchy via spatial lookup
                     hierarchy = {
@@ -399,26 +1076,349 @@ class LocationSyncer:
                         '_id': row['doc_id'],
                         '_source': {
                             'id': row['doc_id'],
+                            'osm_id': row.get('osm_id'),
+                            'osm_type': row.get('osm_type'),
                             'entity_type': row['entity_type'],
                             'name': row['name'],
+                            'name_ne': name_ne,
                             'name_en': name_en,
+                            'name_romanized': self._romanized_name(row, tags),
+                            'osm_last_modified': osm_last_modified(tags),
                             'municipality': hierarchy.get('municipality'),
                             'municipality_ne': hierarchy.get('municipality_ne'),
                             'district': hierarchy.get('district'),
                             'district_ne': hierarchy.get('district_ne'),
                             'province': hierarchy.get('province'),
                             'province_ne': hierarchy.get('province_ne'),
+                            'province_number': self._province_number(hierarchy.get('province')),
                             'country': 'Nepal',
-                            'boost_score': 0.3,  # Lowest priority
+                            'postal_code': tags.get('addr:postcode'),
+                            'last_updated': datetime.now(timezone.utc).isoformat(),
+                            'boost_score': self._calculate_boost('road', None, hierarchy.get('municipality')),
                             'search_text': row['name']
                         }
                     }
                     yield doc
                     
-            success, failed = helpers.bulk(self.es, generate_docs(), raise_on_error=False)
+            success, failed = self.indexer.index(self._prepare_docs(generate_docs()))
             logger.info(f"Synced {success} roads ({failed} failed)")
             return success
             
+    def sync_amenities(self) -> int:
+        """Sync named amenities (hospitals, schools, bus stops, shops...) from OSM nodes and ways"""
+        query = """
+        SELECT 
+            'amenity_node_' || osm_id::text as doc_id,
+            ABS(osm_id) as osm_id,
+            'node' as osm_type,
+            COALESCE(name, tags->'name') as name,
+            ST_Y(ST_Transform(way, 4326)) as lat,
+            ST_X(ST_Transform(way, 4326)) as lon,
+            ST_AsText(ST_Transform(way, 4326)) as centroid,
+            COALESCE(amenity, tags->'amenity') as amenity,
+            COALESCE(tourism, tags->'tourism') as tourism,
+            COALESCE(shop, tags->'shop') as shop,
+            COALESCE(leisure, tags->'leisure') as leisure,
+            tags
+        FROM planet_osm_point
+        WHERE COALESCE(name, tags->'name') IS NOT NULL
+          AND COALESCE(amenity, tags->'amenity', tourism, tags->'tourism', shop, tags->'shop', leisure, tags->'leisure') IS NOT NULL
+        UNION ALL
+        SELECT 
+            'amenity_way_' || osm_id::text as doc_id,
+            ABS(osm_id) as osm_id,
+            CASE WHEN osm_id < 0 THEN 'relation' ELSE 'way' END as osm_type,
+            COALESCE(name, tags->'name') as name,
+            ST_Y(ST_Transform(ST_PointOnSurface(way), 4326)) as lat,
+            ST_X(ST_Transform(ST_PointOnSurface(way), 4326)) as lon,
+            ST_AsText(ST_Transform(ST_PointOnSurface(way), 4326)) as centroid,
+            COALESCE(amenity, tags->'amenity') as amenity,
+            COALESCE(tourism, tags->'tourism') as tourism,
+            COALESCE(shop, tags->'shop') as shop,
+            COALESCE(leisure, tags->'leisure') as leisure,
+            tags
+        FROM planet_osm_polygon
+        WHERE COALESCE(name, tags->'name') IS NOT NULL
+          AND COALESCE(amenity, tags->'amenity', tourism, tags->'tourism', shop, tags->'shop', leisure, tags->'leisure') IS NOT NULL
+        """
+        
+        with self.conn.cursor(cursor_factory=RealDictCursor) as cur:
+            cur.execute(query)
+            
+            def generate_docs():
+                for row in cur:
+                    if self._skip_invalid(row, 'amenity'):
+                        continue
+                    
+                    tags = row.get('tags') or {}
+                    if isinstance(tags, str):
+                        try:
+                            tags = json.loads(tags) if tags else {}
+                        except ValueError:
+                            tags = {}
+                    name_ne, name_en = self._localized_names(row, tags)
+                    place_type = next((row[key] for key in AMENITY_TAG_KEYS if row.get(key)), None)
+                    
+                    # Reverse-geocode against admin boundaries
+                    hierarchy = {}
+                    if row.get('centroid'):
+                        hierarchy = self._get_parent_admin(row['centroid']) or {}
+                    
+                    doc = {
+                        '_index': self.es_index,
+                        '_id': row['doc_id'],
+                        '_source': {
+                            'id': row['doc_id'],
+                            'osm_id': row.get('osm_id'),
+                            'osm_type': row.get('osm_type'),
+                            'entity_type': 'amenity',
+                            'name': row['name'],
+                            'name_ne': name_ne,
+                            'name_en': name_en,
+                            'name_romanized': self._romanized_name(row, tags),
+                            'osm_last_modified': osm_last_modified(tags),
+                            'place_type': place_type,
+                            'location': {
+                                'lat': row['lat'],
+                                'lon': row['lon']
+                            } if row.get('lat') else None,
+                            'municipality': hierarchy.get('municipality'),
+                            'municipality_ne': hierarchy.get('municipality_ne'),
+                            'district': hierarchy.get('district'),
+                            'district_ne': hierarchy.get('district_ne'),
+                            'province': hierarchy.get('province'),
+                            'province_ne': hierarchy.get('province_ne'),
+                            'province_number': self._province_number(hierarchy.get('province')),
+                            'country': 'Nepal',
+                            'postal_code': tags.get('addr:postcode'),
+                            'last_updated': datetime.now(timezone.utc).isoformat(),
+                            'boost_score': self._calculate_boost('amenity', None, hierarchy.get('municipality')),
+                            'tags': tags,
+                            'search_text': ' '.join(filter(None, [row['name'], name_ne, place_type]))
+                        }
+                    }
+                    yield doc
+                    
+            success, failed = self.indexer.index(self._prepare_docs(generate_docs()))
+            logger.info(f"Synced {success} amenities ({failed} failed)")
+            return success
+            
+    def sync_highways(self) -> int:
+        """Sync named major highways with their centroid and enclosing admin boundaries"""
+        query = """
+        SELECT 
+            'highway_' || id::text as doc_id,
+            ABS(osm_id) as osm_id,
+            CASE WHEN osm_id < 0 THEN 'relation' ELSE 'way' END as osm_type,
+            'highway' as entity_type,
+            name,
+            ST_Y(ST_Centroid(geom)) as lat,
+            ST_X(ST_Centroid(geom)) as lon,
+            ST_AsText(ST_Centroid(geom)) as centroid,
+            highway,
+            tags
+        FROM normalized.named_roads
+        WHERE name IS NOT NULL
+          AND highway IN %s
+        """
+        
+        with self.conn.cursor(cursor_factory=RealDictCursor) as cur:
+            cur.execute(query, (MAJOR_HIGHWAY_CLASSES,))
+            
+            def generate_docs():
+                for row in cur:
+                    if self._skip_invalid(row, 'highway'):
+                        continue
+                    
+                    tags = row.get('tags') or {}
+                    if isinstance(tags, str):
+                        try:
+                            tags = json.loads(tags) if tags else {}
+                        except ValueError:
+                            tags = {}
+                    name_ne, name_en = self._localized_names(row, tags)
+                    
+                    # Admin hierarchy at the highway's centroid via spatial join
+                    hierarchy = {}
+                    if row.get('centroid'):
+                        hierarchy = self._get_parent_admin(row['centroid']) or {}
+                    
+                    doc = {
+                        '_index': self.es_index,
+                        '_id': row['doc_id'],
+                        '_source': {
+                            'id': row['doc_id'],
+                            'osm_id': row.get('osm_id'),
+                            'osm_type': row.get('osm_type'),
+                            'entity_type': row['entity_type'],
+                            'name': row['name'],
+                            'name_ne': name_ne,
+                            'name_en': name_en,
+                            'name_romanized': self._romanized_name(row, tags),
+                            'osm_last_modified': osm_last_modified(tags),
+                            'place_type': row['highway'],
+                            'location': {
+                                'lat': row['lat'],
+                                'lon': row['lon']
+                            } if row.get('lat') else None,
+                            'municipality': hierarchy.get('municipality'),
+                            'municipality_ne': hierarchy.get('municipality_ne'),
+                            'district': hierarchy.get('district'),
+                            'district_ne': hierarchy.get('district_ne'),
+                            'province': hierarchy.get('province'),
+                            'province_ne': hierarchy.get('province_ne'),
+                            'province_number': self._province_number(hierarchy.get('province')),
+                            'country': 'Nepal',
+                            'postal_code': tags.get('addr:postcode'),
+                            'last_updated': datetime.now(timezone.utc).isoformat(),
+                            'boost_score': self._calculate_boost('highway', None, hierarchy.get('municipality')),
+                            'search_text': ' '.join(filter(None, [row['name'], name_ne, tags.get('ref')]))
+                        }
+                    }
+                    yield doc
+                    
+            success, failed = self.indexer.index(self._prepare_docs(generate_docs()))
+            logger.info(f"Synced {success} highways ({failed} failed)")
+            return success
+            
+    def load_municipality_codes(self):
+        """Load official local government codes for municipalities, keyed by lowercase name"""
+        query = """
+        SELECT name, COALESCE(tags->'local_government:code', tags->'ref') as code
+        FROM normalized.admin_boundaries
+        WHERE admin_level = 7
+          AND name IS NOT NULL
+          AND (tags ? 'local_government:code' OR tags ? 'ref')
+        """
+        with self.conn.cursor(cursor_factory=RealDictCursor) as cur:
+            cur.execute(query)
+            self.municipality_codes = {
+                row['name'].strip().lower(): row['code'].strip()
+                for row in cur if row['code'] and row['code'].strip()
+            }
+        logger.info(f"Loaded local government codes for {len(self.municipality_codes)} municipalities")
+        
+    def load_district_headquarters(self):
+        """Load the (osm_type, osm_id) of each district's admin_centre or capital relation member"""
+        # Relation members are kept by osm2pgsql --slim; this reads the jsonb
+        # members column of its middle tables (osm2pgsql 1.9+)
+        query = """
+        SELECT m->>'type' as member_type, (m->>'ref')::bigint as ref
+        FROM planet_osm_rels r, jsonb_array_elements(r.members) m
+        WHERE r.tags->>'boundary' = 'administrative'
+          AND r.tags->>'admin_level' = '6'
+          AND m->>'role' IN ('admin_centre', 'capital')
+        """
+        member_types = {'N': 'node', 'W': 'way', 'R': 'relation'}
+        try:
+            with self.conn.cursor(cursor_factory=RealDictCursor) as cur:
+                cur.execute(query)
+                self.district_headquarters = {
+                    (member_types.get(row['member_type']), row['ref']) for row in cur
+                }
+        except psycopg2.Error as e:
+            # Older middle layouts lack the jsonb members; capital=6 tags still apply
+            self.conn.rollback()
+            logger.warning(f"Could not load district admin centres from relation members: {e}")
+            return
+        logger.info(f"Loaded {len(self.district_headquarters)} district headquarters from relation members")
+        
+    def _is_district_hq(self, row: Dict, tags: Dict) -> bool:
+        """Whether a place is its district's headquarters: an admin_centre member or tagged capital=6"""
+        if (row.get('osm_type'), row.get('osm_id')) in self.district_headquarters:
+            return True
+        return tags.get('capital') == '6'
+        
+    def _prepare_docs(self, docs):
+        """Add derived fields (local government code, phonetic name, kNN vector) and, if enabled, province routing"""
+        for doc in docs:
+            source = doc['_source']
+            if self.reindex_bbox and not self._in_bbox(source.get('location')):
+                continue
+            municipality = source.get('municipality')
+            if municipality:
+                source['local_gov_code'] = self.municipality_codes.get(municipality.strip().lower())
+            if source.get('name_ne'):
+                source['name_ne_phonetic'] = normalize_nepali_phonemes(source['name_ne'])
+            # [lon, lat] for the nearestNeighbors kNN query
+            location = source.get('location')
+            if location:
+                source['location_vector'] = [float(location['lon']), float(location['lat'])]
+            # Must match the routing key search-core derives from the province filter
+            if self.routing_enabled and source.get('province'):
+                doc['_routing'] = source['province'].strip().lower()
+            yield doc
+            
+    def _in_bbox(self, location: Optional[Dict]) -> bool:
+        """Check whether a location falls inside the current reindex bounding box"""
+        if not location:
+            return False
+        bbox = self.reindex_bbox
+        return (bbox['south'] <= float(location['lat']) <= bbox['north']
+                and bbox['west'] <= float(location['lon']) <= bbox['east'])
+        
+    def process_reindex_jobs(self):
+        """Run pending partial reindex jobs queued by search-core's reindexFromOSM mutation"""
+        if not self.es.indices.exists(index=REINDEX_JOBS_INDEX):
+            logger.info("No reindex jobs index, nothing to process")
+            return
+        
+        jobs = self.es.search(
+            index=REINDEX_JOBS_INDEX,
+            query={'term': {'status.keyword': 'pending'}},
+            sort=[{'requested_at': 'asc'}],
+            size=100,
+        )['hits']['hits']
+        if not jobs:
+            logger.info("No pending reindex jobs")
+            return
+        
+        self.load_previous_ids()
+        self.connect_db()
+        try:
+            self.load_municipality_codes()
+            self.load_district_headquarters()
+            for job in jobs:
+                self._run_reindex_job(job['_id'], job['_source'])
+        finally:
+            self.conn.close()
+            
+    def _run_reindex_job(self, job_id: str, job: Dict):
+        """Reindex the documents inside one job's bounding box, recording its status"""
+        logger.info(f"Reindexing province {job['province']} (job {job_id})")
+        self.es.update(index=REINDEX_JOBS_INDEX, id=job_id, doc={'status': 'running'})
+        self.reindex_bbox = job['bbox']
+        try:
+            # The full queries still run; only documents inside the box are indexed
+            indexed = sum((
+                self.sync_places(),
+                self.sync_admin_boundaries(),
+                self.sync_poi(),
+                self.sync_amenities(),
+                self.sync_roads(),
+                self.sync_highways(),
+            ))
+            status = {'status': 'completed', 'indexed_documents': indexed}
+            logger.info(f"Reindex job {job_id} completed: {indexed} documents")
+        except Exception as e:
+            # Roll back the failed statement so later jobs can still query
+            self.conn.rollback()
+            status = {'status': 'failed', 'error': str(e)}
+            logger.error(f"Reindex job {job_id} failed: {e}", exc_info=True)
+        finally:
+            self.reindex_bbox = None
+        status['completed_at'] = datetime.now(timezone.utc).isoformat()
+        self.es.update(index=REINDEX_JOBS_INDEX, id=job_id, doc=status)
+        if status['status'] == 'completed':
+            self._set_index_version()
+            
+    def _set_index_version(self):
+        """Record a new data version in the index _meta, so search-core clients can drop stale caches"""
+        now = datetime.now(timezone.utc)
+        version = now.strftime('%Y%m%dT%H%M%SZ')
+        self.es.indices.put_mapping(index=self.es_index, meta={'version': version, 'last_sync_at': now.isoformat()})
+        logger.info(f"Index version set to {version}")
+        
     def _build_admin_hierarchy(self, row: Dict) -> Dict:
         """Build admin hierarchy for admin boundary entities"""
         admin_level = row.get('admin_level')
@@ -511,8 +1511,62 @@ class LocationSyncer:
             logger.warning(f"Failed to get parent admin: {e}")
             return None
         
-    def _calculate_boost(self, entity_type: str, subtype: Optional[str] = None) -> float:
-        """Calculate search boost score based on entity type"""
+    def _skip_invalid(self, row: Dict, entity_type: str) -> bool:
+        """Validate a feature row, counting the reasons if it should be skipped"""
+        errors = validate_feature(row, entity_type)
+        if not errors:
+            return False
+        logger.debug(f"Skipping invalid {entity_type} {row.get('doc_id')}: {', '.join(errors)}")
+        self.skipped.update(errors)
+        self.invalid_by_type[entity_type] += 1
+        return True
+        
+    def _localized_names(self, row: Dict, tags: Dict) -> Tuple[Optional[str], Optional[str]]:
+        """Resolve the Nepali and English names, falling back when the language tags are missing:
+        name_ne = name:ne ?? name (when Devanagari)
+        name_en = name:en ?? name:romanized ?? transliterate(name_ne) ?? name
+        """
+        name = row.get('name')
+        name_ne = tags.get('name:ne') or row.get('name_ne')
+        if not name_ne and has_devanagari(name):
+            name_ne = name
+        
+        name_en = tags.get('name:en') or tags.get('name:romanized')
+        if not name_en:
+            for text in (name_ne, name):
+                if has_devanagari(text):
+                    name_en = transliterate_devanagari(text)
+                    break
+        return name_ne, name_en or name
+        
+    def _romanized_name(self, row: Dict, tags: Dict) -> Optional[str]:
+        """Use the name:romanized tag, otherwise transliterate the Nepali name"""
+        if tags.get('name:romanized'):
+            return tags['name:romanized']
+        for name in (row.get('name_ne'), row.get('name')):
+            if has_devanagari(name):
+                return transliterate_devanagari(name)
+        return None
+        
+    def _province_number(self, province: Optional[str], tags: Optional[Dict] = None) -> Optional[int]:
+        """Resolve province number from OSM tags (province boundaries only) or the fixed name mapping"""
+        if tags:
+            for key in ('province:number', 'ref'):
+                value = tags.get(key)
+                if value and value.strip().isdigit():
+                    return int(value.strip())
+        if not province:
+            return None
+        first_word = province.strip().split()[0].lower() if province.strip() else ''
+        return PROVINCE_NUMBERS.get(first_word)
+        
+    def _calculate_boost(self, entity_type: str, subtype: Optional[str] = None,
+                         municipality: Optional[str] = None) -> float:
+        """Calculate search boost score from entity type and municipality population"""
+        return self._type_boost(entity_type, subtype) + population_weight(municipality)
+        
+    def _type_boost(self, entity_type: str, subtype: Optional[str] = None) -> float:
+        """Base boost score by entity type"""
         if entity_type == 'place':
             # Prioritize cities, towns over hamlets
             if subtype in ['city', 'town']:
@@ -527,6 +1581,10 @@ class LocationSyncer:
             elif subtype == 4:  # province
                 return 1.5
             return 1.2
+        elif entity_type == 'highway':
+            return 1.0
+        elif entity_type == 'amenity':
+            return 0.7
         elif entity_type == 'poi':
             return 0.5
         else:  # roads
@@ -552,26 +1610,55 @@ class LocationSyncer:
             # Connect to database
             self.connect_db()
             
-            # Create/recreate index
-            self.create_index()
-            
             # Check if index already has data and skip if not forcing
-            if not self.force_recreate and self.es.indices.exists(index=self.es_index):
+            if not self.dry_run and not self.force_recreate and self.es.indices.exists(index=self.es_index):
                 count = self.es.count(index=self.es_index)['count']
                 if count > 0:
                     logger.info(f"Index {self.es_index} already has {count} documents, skipping sync (set FORCE_RECREATE=true to override)")
                     return
             
+            if self.dry_run:
+                logger.info("DRY_RUN=true: validating data without writing to Elasticsearch")
+            else:
+                self.load_previous_ids()
+                # Create/recreate index
+                self.create_index()
+            
+            self.load_municipality_codes()
+            self.load_district_headquarters()
+            
             # Sync all entity types
             total_places = self.sync_places()
             total_admin = self.sync_admin_boundaries()
             total_poi = self.sync_poi()
+            total_amenities = self.sync_amenities()
             total_roads = self.sync_roads()
+            total_highways = self.sync_highways()
+            
+            try:
+                self.sync_province_boundaries()
+            except Exception as e:
+                # Optional import; a failure must not fail the main sync
+                logger.warning(f"Province boundary import failed: {e}")
+            try:
+                self.sync_ward_boundaries()
+            except Exception as e:
+                logger.warning(f"Ward boundary import failed: {e}")
             
             # Summary
-            total = total_places + total_admin + total_poi + total_roads
+            total = total_places + total_admin + total_poi + total_amenities + total_roads + total_highways
             duration = (datetime.now() - start_time).total_seconds()
             
+            if self.skipped:
+                reasons = ', '.join(f"{reason}: {count}" for reason, count in self.skipped.most_common())
+                logger.warning(f"Skipped {sum(self.skipped.values())} invalid features: [{reasons}]")
+            
+            if self.dry_run:
+                self._log_dry_run_report()
+                return
+            
+            self._set_index_version()
+            
             logger.info(f"""
 =================================================================
 SYNC COMPLETED SUCCESSFULLY
@@ -580,7 +1667,9 @@ Total documents indexed: {total}
   - Places: {total_places}
   - Admin Boundaries: {total_admin}
   - POI: {total_poi}
+  - Amenities: {total_amenities}
   - Roads: {total_roads}
+  - Highways: {total_highways}
   
 Duration: {duration:.2f} seconds
 Index: {self.es_index}
@@ -593,8 +1682,35 @@ Index: {self.es_index}
         finally:
             if self.conn:
                 self.conn.close()
+                
+    def _log_dry_run_report(self):
+        """Log parsed/valid/invalid counts per entity type and sample documents"""
+        valid = self.indexer.counts
+        invalid = self.invalid_by_type
+        entity_types = sorted(set(valid) | set(invalid))
+        rows = '\n'.join(
+            f"  - {entity_type}: {valid[entity_type]} valid, {invalid[entity_type]} invalid"
+            for entity_type in entity_types
+        )
+        logger.info(f"""
+=================================================================
+DRY RUN REPORT (nothing was indexed)
+=================================================================
+Total features parsed: {sum(valid.values()) + sum(invalid.values())}
+  Valid: {sum(valid.values())}
+  Invalid: {sum(invalid.values())}
+{rows}
+=================================================================
+        """)
+        for entity_type, samples in sorted(self.indexer.samples.items()):
+            logger.info(f"Sample {entity_type} documents:")
+            for doc in samples:
+                logger.info(json.dumps({'_id': doc.get('_id'), **doc['_source']}, ensure_ascii=False, default=str))
 
 
 if __name__ == '__main__':
     syncer = LocationSyncer()
-    syncer.sync_all()
+    if os.getenv('PROCESS_REINDEX_JOBS', 'false').lower() == 'true':
+        syncer.process_reindex_jobs()
+    else:
+        syncer.sync_all()
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"net"
+	"net/http"
+
+	"search-core/graph"
+)
+
+// actorMiddleware records the caller's identity for audit logging. The
+// X-Forwarded-User header set by an authenticating proxy is only honoured on
+// requests from trustedProxies, since any client can send it; other requests
+// keep the identity adminMiddleware derived from the admin token, or are
+// audited as anonymous.
+func actorMiddleware(trustedProxies []*net.IPNet, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actor := r.Header.Get("X-Forwarded-User"); actor != "" && fromTrustedProxy(r, trustedProxies) {
+			r = r.WithContext(graph.WithActor(r.Context(), actor))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"search-core/graph"
+)
+
+func TestActorMiddleware(t *testing.T) {
+	proxies, err := parseCIDRs("172.18.0.0/16")
+	if err != nil {
+		t.Fatalf("parseCIDRs: %v", err)
+	}
+
+	var actor string
+	handler := adminMiddleware("secret", actorMiddleware(proxies, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor = graph.ActorFromContext(r.Context())
+	})))
+
+	tests := []struct {
+		name          string
+		remoteAddr    string
+		forwardedUser string
+		token         string
+		want          string
+	}{
+		{"trusted proxy", "172.18.0.3:40000", "sita", "", "sita"},
+		{"trusted proxy with the admin key", "172.18.0.3:40000", "sita", "secret", "sita"},
+		{"direct client spoofing the header", "203.0.113.7:51234", "sita", "", "anonymous"},
+		{"direct admin spoofing the header", "203.0.113.7:51234", "sita", "secret", adminActor("secret")},
+		{"direct admin", "203.0.113.7:51234", "", "secret", adminActor("secret")},
+		{"wrong admin key", "203.0.113.7:51234", "", "guess", "anonymous"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwardedUser != "" {
+				r.Header.Set("X-Forwarded-User", tt.forwardedUser)
+			}
+			if tt.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+			if actor != tt.want {
+				t.Errorf("actor = %q, want %q", actor, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdminActorHidesKey(t *testing.T) {
+	actor := adminActor("secret")
+	if actor == adminActor("other") {
+		t.Error("different admin keys share an audit identity")
+	}
+	if len(actor) != len("admin-key:")+12 {
+		t.Errorf("adminActor = %q, want a 12-character hash", actor)
+	}
+}
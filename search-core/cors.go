@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+// corsMiddleware adds CORS headers for allowed origins and answers preflight requests.
+// allowedOrigins may contain "*" to allow any origin.
+func corsMiddleware(allowedOrigins []string, next http.Handler) http.Handler {
+	allowAll := false
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+		allowed[origin] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && (allowAll || allowed[origin]) {
+			h := w.Header()
+			if allowAll {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+				h.Add("Vary", "Origin")
+			}
+			h.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			h.Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			h.Set("Access-Control-Expose-Headers", "X-Search-ID")
+			h.Set("Access-Control-Max-Age", "86400")
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseAllowedOrigins splits a comma-separated origin list, dropping empty entries
+func parseAllowedOrigins(value string) []string {
+	var origins []string
+	for _, origin := range strings.Split(value, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"context"
+
+	"search-core/graph/model"
+)
+
+// addressProximityMeters is the distance at which two addresses stop
+// contributing proximity to their similarity score
+const addressProximityMeters = 5000
+
+// CompareAddresses resolves two free-text addresses to their top search
+// results and compares them. Returns nil if either address matches nothing.
+func (r *queryResolver) CompareAddresses(ctx context.Context, a string, b string) (*model.AddressComparison, error) {
+	locA, err := r.topLocation(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	locB, err := r.topLocation(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	if locA == nil || locB == nil {
+		return nil, nil
+	}
+
+	sameMunicipality := sameAdminName(strVal(locA.Municipality), strVal(locB.Municipality))
+	comparison := &model.AddressComparison{
+		SameProvince:     sameAdminName(strVal(locA.Province), strVal(locB.Province)),
+		SameDistrict:     sameAdminName(strVal(locA.District), strVal(locB.District)),
+		SameMunicipality: sameMunicipality,
+		SameWard:         sameMunicipality && locA.Ward != nil && locB.Ward != nil && *locA.Ward == *locB.Ward,
+	}
+	if locA.Location != nil && locB.Location != nil {
+		distance := haversineMeters(locA.Location.Lat, locA.Location.Lon, locB.Location.Lat, locB.Location.Lon)
+		comparison.DistanceMeters = &distance
+	}
+	comparison.Score = addressSimilarity(locA.ID == locB.ID, comparison)
+
+	return comparison, nil
+}
+
+// topLocation returns the best search result for an address, or nil if none
+func (r *queryResolver) topLocation(ctx context.Context, address string) (*model.Location, error) {
+	response, err := r.SearchLocation(ctx, model.LocationSearchInput{Query: address, Limit: intPtr(1)})
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Results) == 0 {
+		return nil, nil
+	}
+	return response.Results[0], nil
+}
+
+// addressSimilarity scores two resolved addresses from 0.0 to 1.0. The same
+// location scores 1.0; otherwise shared admin divisions and proximity add up,
+// with the municipality weighted most as the finest reliable division.
+func addressSimilarity(sameLocation bool, c *model.AddressComparison) float64 {
+	if sameLocation {
+		return 1
+	}
+
+	score := 0.0
+	if c.SameProvince {
+		score += 0.15
+	}
+	if c.SameDistrict {
+		score += 0.2
+	}
+	if c.SameMunicipality {
+		score += 0.3
+	}
+	if c.SameWard {
+		score += 0.15
+	}
+	if c.DistanceMeters != nil {
+		score += 0.2 * max(0, 1-*c.DistanceMeters/addressProximityMeters)
+	}
+	return score
+}
@@ -0,0 +1,28 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+type adminKey struct{}
+
+// WithAdmin marks the request as made by an authenticated administrator
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey{}, true)
+}
+
+// isAdmin reports whether WithAdmin marked the request
+func isAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin
+}
+
+// AdminDirective implements @admin, rejecting fields requested without admin credentials
+func AdminDirective(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
+	if !isAdmin(ctx) {
+		return nil, forbiddenError("This operation requires admin credentials")
+	}
+	return next(ctx)
+}
@@ -0,0 +1,104 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"search-core/graph/model"
+)
+
+// adminLevelFields maps the admin levels that can be aggregated to the
+// document field naming each location's division at that level
+var adminLevelFields = map[int]string{
+	adminLevelProvince:     "province",
+	adminLevelDistrict:     "district",
+	adminLevelMunicipality: "municipality",
+}
+
+// maxAggregationBuckets comfortably exceeds Nepal's 753 local governments
+const maxAggregationBuckets = 1000
+
+// AggregateByAdminLevel counts indexed locations per admin division at level
+func (r *queryResolver) AggregateByAdminLevel(ctx context.Context, entityType *string, level int) ([]*model.LocationAggregation, error) {
+	field, ok := adminLevelFields[level]
+	if !ok {
+		return nil, userError("level must be 4 (province), 6 (district) or 7 (municipality)")
+	}
+
+	var filters []map[string]interface{}
+	if entityType != nil && *entityType != "" {
+		filters = append(filters, map[string]interface{}{
+			"term": map[string]interface{}{"entity_type": *entityType},
+		})
+	}
+
+	query := map[string]interface{}{
+		"size": 0,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{"filter": filters},
+		},
+		"aggs": map[string]interface{}{
+			"divisions": map[string]interface{}{
+				"terms": map[string]interface{}{
+					"field": field + ".keyword",
+					"size":  maxAggregationBuckets,
+					"order": map[string]interface{}{"_key": "asc"},
+				},
+				"aggs": map[string]interface{}{
+					"name_ne": map[string]interface{}{
+						"terms": map[string]interface{}{"field": field + "_ne.keyword", "size": 1},
+					},
+					"centroid": map[string]interface{}{
+						"geo_centroid": map[string]interface{}{"field": "location"},
+					},
+				},
+			},
+		},
+	}
+
+	esResponse, err := r.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var aggs struct {
+		Divisions struct {
+			Buckets []struct {
+				Key      string `json:"key"`
+				DocCount int    `json:"doc_count"`
+				NameNe   struct {
+					Buckets []struct {
+						Key string `json:"key"`
+					} `json:"buckets"`
+				} `json:"name_ne"`
+				Centroid struct {
+					Location *ESGeoPoint `json:"location"`
+				} `json:"centroid"`
+			} `json:"buckets"`
+		} `json:"divisions"`
+	}
+	if err := json.Unmarshal(esResponse.Aggregations, &aggs); err != nil {
+		return nil, fmt.Errorf("error parsing aggregations: %w", err)
+	}
+
+	aggregations := make([]*model.LocationAggregation, 0, len(aggs.Divisions.Buckets))
+	for _, bucket := range aggs.Divisions.Buckets {
+		aggregation := &model.LocationAggregation{
+			Name:  bucket.Key,
+			Count: bucket.DocCount,
+		}
+		if len(bucket.NameNe.Buckets) > 0 {
+			aggregation.NameNe = strPtr(bucket.NameNe.Buckets[0].Key)
+		}
+		// The centroid is absent when no location in the bucket has coordinates
+		if bucket.Centroid.Location != nil {
+			aggregation.Centroid = &model.GeoPoint{
+				Lat: bucket.Centroid.Location.Lat,
+				Lon: bucket.Centroid.Location.Lon,
+			}
+		}
+		aggregations = append(aggregations, aggregation)
+	}
+	return aggregations, nil
+}
@@ -0,0 +1,158 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"search-core/graph/model"
+	"search-core/internal/normalize"
+)
+
+// alertsIndex stores location alerts as percolator queries. The syncer creates
+// it with the locations mapping, which percolator queries need.
+const alertsIndex = "nepal_location_alerts"
+
+// locationAlert is a stored alert document
+type locationAlert struct {
+	Query       interface{} `json:"query"`
+	WebhookURL  string      `json:"webhook_url"`
+	SearchQuery string      `json:"search_query"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// CreateLocationAlert stores a search as a percolator query. The es-syncer
+// percolates locations it newly indexes against it and POSTs matches to webhookURL.
+func (r *mutationResolver) CreateLocationAlert(ctx context.Context, input model.LocationSearchInput, webhookURL string) (*model.Alert, error) {
+	if err := validateWebhookURL(ctx, webhookURL); err != nil {
+		return nil, err
+	}
+
+	input.Query = normalize.SanitizeQuery(input.Query)
+	input = r.normalizeInput(input)
+
+	exists, err := r.indexExists(ctx, alertsIndex)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("alerts index %s does not exist; run the syncer to create it", alertsIndex)
+	}
+
+	alert := locationAlert{
+		Query:       r.buildSearchQuery(input, 1)["query"],
+		WebhookURL:  webhookURL,
+		SearchQuery: input.Query,
+		CreatedAt:   time.Now().UTC(),
+	}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(alert); err != nil {
+		return nil, fmt.Errorf("error encoding alert: %w", err)
+	}
+
+	id := uuid.NewString()
+	res, err := r.ESClient.Index(alertsIndex, &buf,
+		r.ESClient.Index.WithContext(ctx),
+		r.ESClient.Index.WithDocumentID(id),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error indexing alert: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+
+	return &model.Alert{
+		ID:         id,
+		Query:      alert.SearchQuery,
+		WebhookURL: webhookURL,
+		CreatedAt:  alert.CreatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// internalHostSuffixes are host name suffixes that only resolve inside the deployment
+var internalHostSuffixes = []string{".local", ".localhost", ".internal", ".lan", ".home.arpa"}
+
+// sharedAddressSpace is the carrier-grade NAT range, private but not covered by IsPrivate
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// lookupIPAddr resolves webhook hosts; tests replace it
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// validateWebhookURL rejects webhook URLs that are not absolute http(s) URLs
+// on a public host: single-label and internal names, and hosts resolving to
+// loopback, private, link-local or other non-public addresses
+func validateWebhookURL(ctx context.Context, webhookURL string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return userError("webhookUrl must be an absolute http or https URL")
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if _, err := netip.ParseAddr(host); err != nil {
+		if host == "localhost" || !strings.Contains(host, ".") || hasAnySuffix(host, internalHostSuffixes) {
+			return userError("webhookUrl must point to a public host")
+		}
+	}
+
+	addrs, err := lookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return userError("webhookUrl host %s could not be resolved", host)
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return userError("webhookUrl must point to a public host")
+		}
+	}
+	return nil
+}
+
+// publicIP reports whether ip is a globally routable unicast address
+func publicIP(ip net.IP) bool {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// hasAnySuffix reports whether s ends with one of suffixes
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexExists reports whether an index exists
+func (r *Resolver) indexExists(ctx context.Context, index string) (bool, error) {
+	res, err := r.ESClient.Indices.Exists([]string{index}, r.ESClient.Indices.Exists.WithContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("error checking index: %w", err)
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, fmt.Errorf("elasticsearch error: %s", res.Status())
+}
@@ -0,0 +1,68 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	// Resolve names without DNS; rebind.example has one internal address
+	hosts := map[string][]string{
+		"hooks.example.com": {"93.184.216.34"},
+		"rebind.example":    {"93.184.216.34", "10.0.0.5"},
+	}
+	lookup := lookupIPAddr
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		if ip := net.ParseIP(host); ip != nil {
+			return []net.IPAddr{{IP: ip}}, nil
+		}
+		var addrs []net.IPAddr
+		for _, addr := range hosts[host] {
+			addrs = append(addrs, net.IPAddr{IP: net.ParseIP(addr)})
+		}
+		if addrs == nil {
+			return nil, errors.New("no such host")
+		}
+		return addrs, nil
+	}
+	t.Cleanup(func() { lookupIPAddr = lookup })
+
+	tests := []struct {
+		url     string
+		allowed bool
+	}{
+		{"https://hooks.example.com/alerts", true},
+		{"http://93.184.216.34:8080/hook", true},
+		{"ftp://hooks.example.com/alerts", false},
+		{"/relative/path", false},
+		{"http://127.0.0.1/hook", false},
+		{"http://[::1]/hook", false},
+		{"http://10.1.2.3/hook", false},
+		{"http://192.168.1.10/hook", false},
+		{"http://100.64.0.1/hook", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://[fe80::1]/hook", false},
+		{"http://0.0.0.0/hook", false},
+		{"http://[::ffff:127.0.0.1]/hook", false},
+		{"http://localhost:8080/hook", false},
+		{"http://elasticsearch:9200/_search", false},
+		{"http://metadata.google.internal/", false},
+		{"http://printer.local/", false},
+		{"http://rebind.example/hook", false},
+		{"http://unresolvable.example/hook", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := validateWebhookURL(t.Context(), tt.url)
+			if tt.allowed && err != nil {
+				t.Errorf("validateWebhookURL(%q) = %v, want allowed", tt.url, err)
+			}
+			if !tt.allowed && err == nil {
+				t.Errorf("validateWebhookURL(%q) allowed, want rejected", tt.url)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	elasticsearch "github.com/elastic/go-elasticsearch/v8"
+	"github.com/google/uuid"
+
+	"search-core/graph/model"
+)
+
+// auditLogIndex is the Elasticsearch index that receives mutation audit entries
+const auditLogIndex = "nepal_audit_log"
+
+// auditEntry records one successful mutation of a location
+type auditEntry struct {
+	EntryID     string      `json:"entry_id"`
+	Timestamp   time.Time   `json:"timestamp"`
+	Operation   string      `json:"operation"`
+	LocationID  string      `json:"location_id"`
+	Actor       string      `json:"actor"`
+	BeforeState interface{} `json:"before_state,omitempty"`
+	AfterState  interface{} `json:"after_state,omitempty"`
+}
+
+type actorKey struct{}
+
+// WithActor returns a context carrying the identity recorded in audit entries
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the audited identity, or "anonymous" if none was set
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return "anonymous"
+}
+
+// AuditLogger indexes mutation audit entries asynchronously so auditing never
+// blocks a mutation response
+type AuditLogger struct {
+	esClient *elasticsearch.Client
+	entries  chan auditEntry
+}
+
+// NewAuditLogger creates an AuditLogger and starts its background worker
+func NewAuditLogger(esClient *elasticsearch.Client, bufferSize int) *AuditLogger {
+	l := &AuditLogger{
+		esClient: esClient,
+		entries:  make(chan auditEntry, bufferSize),
+	}
+	go l.run()
+	return l
+}
+
+// Record queues an audit entry for a successful mutation. Entries are dropped
+// (with a warning) when the buffer is full.
+func (l *AuditLogger) Record(ctx context.Context, operation, locationID string, before, after interface{}) {
+	entry := auditEntry{
+		EntryID:     uuid.NewString(),
+		Timestamp:   time.Now().UTC(),
+		Operation:   operation,
+		LocationID:  locationID,
+		Actor:       ActorFromContext(ctx),
+		BeforeState: before,
+		AfterState:  after,
+	}
+
+	select {
+	case l.entries <- entry:
+	default:
+		log.Printf("WARNING: audit log buffer full, dropping %s entry for %s", operation, locationID)
+	}
+}
+
+// run indexes queued entries until the channel is closed
+func (l *AuditLogger) run() {
+	for entry := range l.entries {
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(entry); err != nil {
+			log.Printf("Error encoding audit entry: %v", err)
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		res, err := l.esClient.Index(auditLogIndex, &buf,
+			l.esClient.Index.WithContext(ctx),
+			l.esClient.Index.WithDocumentID(entry.EntryID),
+		)
+		cancel()
+		if err != nil {
+			log.Printf("Error indexing audit entry: %v", err)
+			continue
+		}
+		if res.IsError() {
+			log.Printf("Error indexing audit entry: %s", res.Status())
+		}
+		res.Body.Close()
+	}
+}
+
+// recordAudit records a mutation when audit logging is enabled
+func (r *Resolver) recordAudit(ctx context.Context, operation, locationID string, before, after interface{}) {
+	if r.AuditLogger != nil {
+		r.AuditLogger.Record(ctx, operation, locationID, before, after)
+	}
+}
+
+// ListAuditLog lists audit entries newest first, optionally for one location
+func (r *queryResolver) ListAuditLog(ctx context.Context, locationID *string, limit *int, after *string) (*model.AuditLogPage, error) {
+	size := resolveLimit(limit)
+
+	filters := []map[string]interface{}{}
+	if locationID != nil && *locationID != "" {
+		filters = append(filters, map[string]interface{}{
+			"term": map[string]interface{}{"location_id.keyword": *locationID},
+		})
+	}
+
+	query := map[string]interface{}{
+		"size": size,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{"filter": filters},
+		},
+		"sort": []map[string]interface{}{
+			{"timestamp": map[string]interface{}{"order": "desc"}},
+			{"entry_id.keyword": map[string]interface{}{"order": "asc"}},
+		},
+	}
+	if after != nil {
+		searchAfter, err := decodeCursor(*after)
+		if err != nil {
+			return nil, err
+		}
+		query["search_after"] = searchAfter
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+		return nil, fmt.Errorf("error encoding query: %w", err)
+	}
+
+	// The audit index only exists once something has been audited
+	res, err := r.ESClient.Search(
+		r.ESClient.Search.WithContext(ctx),
+		r.ESClient.Search.WithIndex(auditLogIndex),
+		r.ESClient.Search.WithBody(&buf),
+		r.ESClient.Search.WithTrackTotalHits(true),
+		r.ESClient.Search.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error executing search: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+
+	var esResponse struct {
+		Hits struct {
+			Total struct {
+				Value int `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				Source struct {
+					Timestamp   time.Time       `json:"timestamp"`
+					Operation   string          `json:"operation"`
+					LocationID  string          `json:"location_id"`
+					Actor       string          `json:"actor"`
+					BeforeState json.RawMessage `json:"before_state"`
+					AfterState  json.RawMessage `json:"after_state"`
+				} `json:"_source"`
+				Sort []interface{} `json:"sort"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&esResponse); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	hits := esResponse.Hits.Hits
+	page := &model.AuditLogPage{
+		Items: make([]*model.AuditLogEntry, 0, len(hits)),
+		Total: esResponse.Hits.Total.Value,
+	}
+	for _, hit := range hits {
+		page.Items = append(page.Items, &model.AuditLogEntry{
+			Timestamp:   hit.Source.Timestamp.Format(time.RFC3339),
+			Operation:   hit.Source.Operation,
+			LocationID:  hit.Source.LocationID,
+			Actor:       hit.Source.Actor,
+			BeforeState: rawJSONToStr(hit.Source.BeforeState),
+			AfterState:  rawJSONToStr(hit.Source.AfterState),
+		})
+	}
+	if len(hits) == size {
+		page.NextCursor = encodeCursor(hits[len(hits)-1].Sort)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// fieldBoost is a searched field and its query-time boost
+type fieldBoost struct {
+	field string
+	boost float64
+}
+
+// textMatchFields are the fields searched by fuzzy text matching, with their default boosts
+var textMatchFields = []fieldBoost{
+	{"name", 3}, {"name_ne", 3}, {"name_en", 3},
+	{"name.fuzzy", 2}, {"name_ne.fuzzy", 2}, {"name_en.fuzzy", 2},
+	{"name_romanized", 2}, {"name_romanized._2gram", 1}, {"name_romanized._3gram", 1},
+	{"search_text", 1},
+}
+
+// prefixMatchFields are the fields searched by prefix matching, with their default boosts
+var prefixMatchFields = []fieldBoost{
+	{"name", 3}, {"name_ne", 3}, {"name_en", 3}, {"name_romanized", 2},
+}
+
+// ParseFieldBoosts parses an ES_FIELD_BOOSTS value such as
+// "name^4,name_ne^5,search_text^1". A field without ^ gets a boost of 1.
+// Only fields used by text or prefix matching may be boosted.
+func ParseFieldBoosts(spec string) (map[string]float64, error) {
+	boosts := make(map[string]float64)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		field, boostStr, hasBoost := strings.Cut(entry, "^")
+		field = strings.TrimSpace(field)
+		if !isBoostableField(field) {
+			return nil, fmt.Errorf("unknown search field %q", field)
+		}
+
+		boost := 1.0
+		if hasBoost {
+			var err error
+			boost, err = strconv.ParseFloat(strings.TrimSpace(boostStr), 64)
+			if err != nil || boost <= 0 {
+				return nil, fmt.Errorf("invalid boost %q for field %s", boostStr, field)
+			}
+		}
+		boosts[field] = boost
+	}
+	return boosts, nil
+}
+
+// isBoostableField reports whether field is searched by text or prefix matching
+func isBoostableField(field string) bool {
+	for _, f := range textMatchFields {
+		if f.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// boostedFields formats fields for a multi_match query, applying overrides
+// in place of the default boosts
+func boostedFields(fields []fieldBoost, overrides map[string]float64) []string {
+	formatted := make([]string, 0, len(fields))
+	for _, f := range fields {
+		boost := f.boost
+		if override, ok := overrides[f.field]; ok {
+			boost = override
+		}
+		if boost == 1 {
+			formatted = append(formatted, f.field)
+			continue
+		}
+		formatted = append(formatted, f.field+"^"+strconv.FormatFloat(boost, 'f', -1, 64))
+	}
+	return formatted
+}
@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFieldBoosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    map[string]float64
+		wantErr bool
+	}{
+		{"single field", "name^4", map[string]float64{"name": 4}, false},
+		{"several fields", "name^4,name_ne^5,search_text^1", map[string]float64{"name": 4, "name_ne": 5, "search_text": 1}, false},
+		{"fractional boost", "name_romanized^1.5", map[string]float64{"name_romanized": 1.5}, false},
+		{"missing boost defaults to 1", "search_text", map[string]float64{"search_text": 1}, false},
+		{"subfield", "name.fuzzy^2", map[string]float64{"name.fuzzy": 2}, false},
+		{"whitespace and empty entries", " name ^ 4 ,, name_en^2 ", map[string]float64{"name": 4, "name_en": 2}, false},
+		{"empty", "", map[string]float64{}, false},
+		{"unknown field", "population^2", nil, true},
+		{"non-numeric boost", "name^high", nil, true},
+		{"zero boost", "name^0", nil, true},
+		{"negative boost", "name^-1", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFieldBoosts(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseFieldBoosts(%q) = %v, want an error", tt.spec, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFieldBoosts(%q): %v", tt.spec, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFieldBoosts(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoostedFields(t *testing.T) {
+	got := boostedFields(prefixMatchFields, map[string]float64{"name_ne": 5, "name_romanized": 1})
+	want := []string{"name^3", "name_ne^5", "name_en^3", "name_romanized"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("boostedFields = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,108 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrCacheMiss is returned by CacheClient.Get when the key is not cached
+var ErrCacheMiss = errors.New("cache miss")
+
+// CacheClient is a key/value store used to cache search responses
+type CacheClient interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// RedisCache implements CacheClient on top of a Redis client
+type RedisCache struct {
+	Client *redis.Client
+}
+
+// Get returns the cached value or ErrCacheMiss
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	val, err := c.Client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrCacheMiss
+	}
+	return val, err
+}
+
+// Set stores the value with the given TTL
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.Client.Set(ctx, key, value, ttl).Err()
+}
+
+// FallbackCache wraps a primary CacheClient (usually Redis) and degrades to a
+// fixed-size in-memory LRU when the primary is unavailable
+type FallbackCache struct {
+	primary CacheClient
+	local   *expirable.LRU[string, []byte]
+	group   singleflight.Group
+}
+
+// NewFallbackCache creates a FallbackCache. primary may be nil, in which case
+// only the in-memory LRU is used.
+func NewFallbackCache(primary CacheClient, size int, ttl time.Duration) *FallbackCache {
+	return &FallbackCache{
+		primary: primary,
+		local:   expirable.NewLRU[string, []byte](size, nil, ttl),
+	}
+}
+
+// Get reads from the primary cache, falling back to the LRU on error
+func (c *FallbackCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if c.primary != nil {
+		val, err := c.primary.Get(ctx, key)
+		if err == nil || errors.Is(err, ErrCacheMiss) {
+			return val, err
+		}
+		log.Printf("WARNING: cache unavailable, using in-memory fallback: %v", err)
+	}
+
+	if val, ok := c.local.Get(key); ok {
+		return val, nil
+	}
+	return nil, ErrCacheMiss
+}
+
+// Set writes to the primary cache, falling back to the LRU on error
+func (c *FallbackCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if c.primary != nil {
+		err := c.primary.Set(ctx, key, value, ttl)
+		if err == nil {
+			return nil
+		}
+		log.Printf("WARNING: cache unavailable, using in-memory fallback: %v", err)
+	}
+
+	c.local.Add(key, value)
+	return nil
+}
+
+// Fetch returns the cached value for key, calling load on a miss and caching
+// its result. Concurrent misses for the same key share a single load.
+func (c *FallbackCache) Fetch(ctx context.Context, key string, ttl time.Duration, load func() ([]byte, error)) ([]byte, error) {
+	if val, err := c.Get(ctx, key); err == nil {
+		return val, nil
+	}
+
+	val, err, _ := c.group.Do(key, func() (interface{}, error) {
+		val, err := load()
+		if err != nil {
+			return nil, err
+		}
+		c.Set(ctx, key, val, ttl)
+		return val, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val.([]byte), nil
+}
@@ -0,0 +1,49 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Cache-Control values for GraphQL responses
+const (
+	cacheControlQuery    = "public, max-age=300"
+	cacheControlMutation = "no-store"
+	// Debug output (explain, profiles) and admin data must not be kept by shared caches
+	cacheControlPrivate = "private, max-age=0"
+)
+
+// CacheControlMiddleware is a gqlgen operation middleware that sets the
+// Cache-Control response header from the operation type
+func CacheControlMiddleware(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	// Subscriptions run over an upgraded WebSocket connection with no HTTP response to annotate
+	if oc.Operation != nil && oc.Operation.Operation != ast.Subscription {
+		setResponseHeader(ctx, "Cache-Control", cacheControl(ctx, oc))
+	}
+	return next(ctx)
+}
+
+// cacheControl picks the Cache-Control value for an operation
+func cacheControl(ctx context.Context, oc *graphql.OperationContext) string {
+	if oc.Operation.Operation == ast.Mutation {
+		return cacheControlMutation
+	}
+	if isAdmin(ctx) {
+		return cacheControlPrivate
+	}
+
+	for _, selection := range oc.Operation.SelectionSet {
+		field, ok := selection.(*ast.Field)
+		if !ok || field.Name != "searchLocation" {
+			continue
+		}
+		input, _ := field.ArgumentMap(oc.Variables)["input"].(map[string]interface{})
+		if input["explain"] == true || input["profileQuery"] == true {
+			return cacheControlPrivate
+		}
+	}
+	return cacheControlQuery
+}
@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"search-core/graph/model"
+)
+
+// maxClusters caps the number of geohash cells returned by a cluster search
+const maxClusters = 1000
+
+// clusterSearch groups matches into geohash cells with a geohash_grid
+// aggregation instead of returning individual results
+func (r *queryResolver) clusterSearch(ctx context.Context, input model.LocationSearchInput, limit int) (*model.LocationSearchResponse, error) {
+	precision := *input.ClusterByGeohash
+	if precision < 1 || precision > 12 {
+		return nil, userError("clusterByGeohash must be between 1 and 12")
+	}
+
+	query := r.buildSearchQuery(input, limit)
+	delete(query, "sort")
+	delete(query, "from")
+	query["size"] = 0
+	query["aggs"] = map[string]interface{}{
+		"clusters": map[string]interface{}{
+			"geohash_grid": map[string]interface{}{
+				"field":     "location",
+				"precision": precision,
+				"size":      maxClusters,
+			},
+			"aggs": map[string]interface{}{
+				"centroid": map[string]interface{}{
+					"geo_centroid": map[string]interface{}{"field": "location"},
+				},
+				"top": map[string]interface{}{
+					"top_hits": map[string]interface{}{
+						"size":    1,
+						"_source": map[string]interface{}{"includes": []string{"name"}},
+					},
+				},
+			},
+		},
+	}
+
+	esResponse, err := r.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var aggs struct {
+		Clusters struct {
+			Buckets []struct {
+				Key      string `json:"key"`
+				DocCount int    `json:"doc_count"`
+				Centroid struct {
+					Location ESGeoPoint `json:"location"`
+				} `json:"centroid"`
+				Top struct {
+					Hits struct {
+						Hits []ESHit `json:"hits"`
+					} `json:"hits"`
+				} `json:"top"`
+			} `json:"buckets"`
+		} `json:"clusters"`
+	}
+	if err := json.Unmarshal(esResponse.Aggregations, &aggs); err != nil {
+		return nil, fmt.Errorf("error parsing aggregations: %w", err)
+	}
+
+	clusters := make([]*model.GeohashCluster, 0, len(aggs.Clusters.Buckets))
+	for _, bucket := range aggs.Clusters.Buckets {
+		cluster := &model.GeohashCluster{
+			Geohash: bucket.Key,
+			Centroid: &model.GeoPoint{
+				Lat: bucket.Centroid.Location.Lat,
+				Lon: bucket.Centroid.Location.Lon,
+			},
+			Count: bucket.DocCount,
+		}
+		if len(bucket.Top.Hits.Hits) > 0 {
+			cluster.TopLocationName = bucket.Top.Hits.Hits[0].Source.Name
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	return &model.LocationSearchResponse{
+		Results:  []*model.Location{},
+		Total:    esResponse.Hits.Total.Value,
+		Took:     esResponse.Took,
+		Clusters: clusters,
+
+		QueryInterpretation: strPtr(describeInterpretation(input)),
+	}, nil
+}
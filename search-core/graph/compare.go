@@ -0,0 +1,59 @@
+package graph
+
+import (
+	"context"
+	"math"
+
+	"search-core/graph/model"
+)
+
+// earthRadiusMeters is the mean Earth radius used for Haversine distances
+const earthRadiusMeters = 6371008.8
+
+// LocationCompare compares the administrative hierarchy and distance of two locations
+func (r *queryResolver) LocationCompare(ctx context.Context, idA string, idB string) (*model.LocationComparison, error) {
+	a, err := r.getLocation(ctx, idA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := r.getLocation(ctx, idB)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil || b == nil {
+		return nil, nil
+	}
+
+	srcA, srcB := a.Source, b.Source
+	sameMunicipality := sameAdminName(srcA.Municipality, srcB.Municipality)
+	comparison := &model.LocationComparison{
+		SameProvince:     sameAdminName(srcA.Province, srcB.Province),
+		SameDistrict:     sameAdminName(srcA.District, srcB.District),
+		SameMunicipality: sameMunicipality,
+		SameWard:         sameMunicipality && srcA.Ward != 0 && srcA.Ward == srcB.Ward,
+	}
+
+	pointA, pointB := convertGeoPoint(srcA.Location), convertGeoPoint(srcB.Location)
+	if pointA != nil && pointB != nil {
+		distance := haversineMeters(pointA.Lat, pointA.Lon, pointB.Lat, pointB.Lon)
+		comparison.DistanceBetweenMeters = &distance
+	}
+
+	return comparison, nil
+}
+
+// sameAdminName reports whether two admin names are both set and equal
+func sameAdminName(a, b string) bool {
+	return a != "" && b != "" && stringsMatch(a, b)
+}
+
+// haversineMeters returns the great-circle distance between two points
+func haversineMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
@@ -0,0 +1,241 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/agnivade/levenshtein"
+
+	"search-core/graph/model"
+)
+
+// maxFuzzyEdits is the largest edit distance of an Elasticsearch fuzzy match
+const maxFuzzyEdits = 2
+
+// applyMatchConfidence sets MatchConfidence on each result: the hit's ES score
+// divided by maxScore, the highest score the query can reach (see
+// maxPossibleScore), capped at 1 and scaled by how closely the query text
+// matches the location's nearest name. results must be in hits order. Nothing
+// is set when maxScore is unknown.
+func applyMatchConfidence(query string, results []*model.Location, hits []ESHit, maxScore float64) {
+	if maxScore <= 0 {
+		return
+	}
+
+	for i, loc := range results {
+		if i >= len(hits) {
+			break
+		}
+		confidence := min(hits[i].Score/maxScore, 1) * nameSimilarity(query, loc)
+		loc.MatchConfidence = &confidence
+	}
+}
+
+// maxPossibleScore returns the score a known-perfect document would get for
+// the search: the top hit's _explain output recomputed as if each field it
+// matched held exactly the query text (see perfectScore). An explanation
+// already fetched, in explain mode or as topExplanation for explainTop, is
+// reused; otherwise one _explain request is made. Failures are logged and
+// return 0, leaving confidence unset.
+func (r *queryResolver) maxPossibleScore(ctx context.Context, input model.LocationSearchInput, limit int, hits []ESHit, topExplanation json.RawMessage) float64 {
+	if len(hits) == 0 || hits[0].Score <= 0 {
+		return 0
+	}
+
+	explanation := hits[0].Explanation
+	if len(explanation) == 0 {
+		explanation = topExplanation
+	}
+	if len(explanation) == 0 {
+		var err error
+		explanation, err = r.explainHit(ctx, r.withStalenessDecay(r.buildSearchQuery(input, limit)["query"]), hits[0])
+		if err != nil {
+			log.Printf("WARNING: failed to explain top result for match confidence: %v", err)
+			return 0
+		}
+	}
+
+	var root explanationNode
+	if err := json.Unmarshal(explanation, &root); err != nil {
+		log.Printf("WARNING: invalid explanation for match confidence: %v", err)
+		return 0
+	}
+	return perfectScore(root, splitQuery(input.Query))
+}
+
+// splitQuery returns the lowercased whitespace-separated terms of a query
+func splitQuery(query string) []string {
+	return strings.Fields(strings.ToLower(query))
+}
+
+// perfectScore recomputes an explanation for a document whose matched fields
+// hold exactly the query terms: each BM25 term score is recomputed with the
+// term occurring once in a field as long as the query, at the boost of an
+// exact (not fuzzy) match. Sums, products and maxima are recombined from
+// their recomputed parts; other nodes are scaled by the change in their
+// parts, and leaves such as constants and function values keep their value.
+func perfectScore(node explanationNode, queryTerms []string) float64 {
+	desc := node.Description
+	switch {
+	case strings.HasPrefix(desc, "weight(") && len(node.Details) == 1:
+		return perfectTermScore(node.Details[0], matchedTerm(desc), queryTerms)
+	case strings.HasSuffix(desc, "sum of:"):
+		total := 0.0
+		for _, d := range node.Details {
+			total += perfectScore(d, queryTerms)
+		}
+		return total
+	case strings.HasSuffix(desc, "product of:"):
+		product := 1.0
+		for _, d := range node.Details {
+			product *= perfectScore(d, queryTerms)
+		}
+		return product
+	case desc == "max of:" || strings.HasPrefix(desc, "max plus "):
+		// dis_max: the best clause plus tie_breaker times the others
+		tieBreaker := 0.0
+		if rest, ok := strings.CutPrefix(desc, "max plus "); ok {
+			tieBreaker, _ = strconv.ParseFloat(strings.Fields(rest)[0], 64)
+		}
+		best, total := 0.0, 0.0
+		for _, d := range node.Details {
+			score := perfectScore(d, queryTerms)
+			best = max(best, score)
+			total += score
+		}
+		return best + tieBreaker*(total-best)
+	}
+
+	actual, perfect := 0.0, 0.0
+	for _, d := range node.Details {
+		actual += d.Value
+		perfect += perfectScore(d, queryTerms)
+	}
+	if actual <= 0 {
+		return node.Value
+	}
+	return node.Value * perfect / actual
+}
+
+// perfectTermScore recomputes a BM25 "score(freq=...)" explanation, computed
+// as boost * idf * tf, for the term occurring once in a field as long as the
+// query. A fuzzy match's boost is restored to that of an exact match.
+func perfectTermScore(node explanationNode, term string, queryTerms []string) float64 {
+	var boost, idf float64
+	var tf *explanationNode
+	for i, d := range node.Details {
+		switch {
+		case d.Description == "boost":
+			boost = d.Value
+		case strings.HasPrefix(d.Description, "idf"):
+			idf = d.Value
+		case strings.HasPrefix(d.Description, "tf"):
+			tf = &node.Details[i]
+		}
+	}
+	if boost == 0 || idf == 0 || tf == nil {
+		return node.Value
+	}
+
+	params := map[string]float64{}
+	for _, d := range tf.Details {
+		name, _, _ := strings.Cut(d.Description, ",")
+		params[name] = d.Value
+	}
+	k1, b, avgdl := params["k1"], params["b"], params["avgdl"]
+	if avgdl <= 0 {
+		return node.Value
+	}
+	dl := float64(max(len(queryTerms), 1))
+	perfectTF := 1 / (1 + k1*(1-b+b*dl/avgdl))
+
+	return boost / fuzzyBoost(term, queryTerms) * idf * perfectTF
+}
+
+// matchedTerm extracts the term from a "weight(field:term in doc) ..." description
+func matchedTerm(description string) string {
+	rest := strings.TrimPrefix(description, "weight(")
+	_, rest, _ = strings.Cut(rest, ":")
+	term, _, _ := strings.Cut(rest, " in ")
+	return term
+}
+
+// fuzzyBoost returns the boost Lucene gives a fuzzy match of term against
+// the closest query term: 1 - edits / length of the shorter term. Exact
+// matches, and terms too far from any query term to be fuzzy matches of it
+// (e.g. n-grams), get 1.
+func fuzzyBoost(term string, queryTerms []string) float64 {
+	if term == "" {
+		return 1
+	}
+	bestEdits, bestLen := -1, 0
+	for _, q := range queryTerms {
+		edits := levenshtein.ComputeDistance(term, q)
+		if bestEdits == -1 || edits < bestEdits {
+			bestEdits = edits
+			bestLen = min(utf8.RuneCountInString(term), utf8.RuneCountInString(q))
+		}
+	}
+	if bestEdits <= 0 || bestEdits > maxFuzzyEdits || bestEdits >= bestLen {
+		return 1
+	}
+	return 1 - float64(bestEdits)/float64(bestLen)
+}
+
+// nameSimilarity returns the best similarity (0.0-1.0) between the query and
+// any of the location's names
+func nameSimilarity(query string, loc *model.Location) float64 {
+	names := []string{loc.Name}
+	if loc.NameNe != nil {
+		names = append(names, *loc.NameNe)
+	}
+	if loc.NameEn != nil {
+		names = append(names, *loc.NameEn)
+	}
+
+	best := 0.0
+	for _, name := range names {
+		if sim := stringSimilarity(query, name); sim > best {
+			best = sim
+		}
+	}
+	return best
+}
+
+// stringSimilarity is 1 minus the normalized Levenshtein distance, case-insensitive
+func stringSimilarity(a, b string) float64 {
+	a = strings.ToLower(strings.TrimSpace(a))
+	b = strings.ToLower(strings.TrimSpace(b))
+	if a == "" || b == "" {
+		return 0
+	}
+
+	maxLen := utf8.RuneCountInString(a)
+	if n := utf8.RuneCountInString(b); n > maxLen {
+		maxLen = n
+	}
+
+	return 1 - float64(levenshtein.ComputeDistance(a, b))/float64(maxLen)
+}
+
+// maxAlternatives is the number of runner-up results offered for an ambiguous query
+const maxAlternatives = 3
+
+// ambiguousAlternatives returns results 2-4 when the second result scores
+// within gapPercent of the top result, so clients can ask "Did you mean one
+// of these?". It returns nil for clear matches or when gapPercent is zero.
+func ambiguousAlternatives(results []*model.Location, gapPercent int) []*model.Location {
+	if gapPercent <= 0 || len(results) < 2 || results[0].Score <= 0 {
+		return nil
+	}
+
+	gap := (results[0].Score - results[1].Score) / results[0].Score * 100
+	if gap >= float64(gapPercent) {
+		return nil
+	}
+	return results[1:min(len(results), 1+maxAlternatives)]
+}
@@ -0,0 +1,247 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"search-core/graph/model"
+)
+
+// termExplanation builds a Lucene BM25 explanation for one matched term
+func termExplanation(field, term string, boost, idf, freq, dl, avgdl float64) explanationNode {
+	const k1, b = 1.2, 0.75
+	tf := freq / (freq + k1*(1-b+b*dl/avgdl))
+	return explanationNode{
+		Value:       boost * idf * tf,
+		Description: "weight(" + field + ":" + term + " in 42) [PerFieldSimilarity], result of:",
+		Details: []explanationNode{{
+			Value:       boost * idf * tf,
+			Description: "score(freq=1.0), computed as boost * idf * tf from:",
+			Details: []explanationNode{
+				{Value: boost, Description: "boost"},
+				{Value: idf, Description: "idf, computed as log(1 + (N - n + 0.5) / (n + 0.5)) from:"},
+				{Value: tf, Description: "tf, computed as freq / (freq + k1 * (1 - b + b * dl / avgdl)) from:", Details: []explanationNode{
+					{Value: freq, Description: "freq, occurrences of term within document"},
+					{Value: k1, Description: "k1, term saturation parameter"},
+					{Value: b, Description: "b, length normalization parameter"},
+					{Value: dl, Description: "dl, length of field"},
+					{Value: avgdl, Description: "avgdl, average length of field"},
+				}},
+			},
+		}},
+	}
+}
+
+// perfectTermValue is the BM25 score of a term occurring once in a field of length dl
+func perfectTermValue(boost, idf, dl, avgdl float64) float64 {
+	return boost * idf / (1 + 1.2*(1-0.75+0.75*dl/avgdl))
+}
+
+func TestPerfectScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		node  explanationNode
+		query string
+		want  float64
+	}{
+		{
+			name:  "exact match in a field as long as the query",
+			node:  termExplanation("name", "kathmandu", 2.2, 3, 1, 1, 2),
+			query: "kathmandu",
+			want:  perfectTermValue(2.2, 3, 1, 2),
+		},
+		{
+			name:  "match in a longer field",
+			node:  termExplanation("name", "kathmandu", 2.2, 3, 1, 3, 2),
+			query: "kathmandu",
+			want:  perfectTermValue(2.2, 3, 1, 2),
+		},
+		{
+			name:  "fuzzy match restores the exact match boost",
+			node:  termExplanation("name", "kathmandu", 2.2*(1-2.0/9), 3, 1, 1, 2),
+			query: "kathmandoo",
+			want:  perfectTermValue(2.2, 3, 1, 2),
+		},
+		{
+			name: "dis_max keeps the best field",
+			node: explanationNode{Value: 5, Description: "max of:", Details: []explanationNode{
+				termExplanation("name", "patan", 2.2*3, 2, 1, 1, 1.5),
+				termExplanation("search_text", "patan", 2.2, 1, 1, 8, 6),
+			}},
+			query: "patan",
+			want:  perfectTermValue(2.2*3, 2, 1, 1.5),
+		},
+		{
+			name: "bool sums its clauses",
+			node: explanationNode{Value: 4, Description: "sum of:", Details: []explanationNode{
+				termExplanation("name", "new", 2.2, 1, 1, 3, 2),
+				termExplanation("name", "road", 2.2, 2, 1, 3, 2),
+			}},
+			query: "new road",
+			want:  perfectTermValue(2.2, 1, 2, 2) + perfectTermValue(2.2, 2, 2, 2),
+		},
+		{
+			name: "function score multiplies by the decay",
+			node: explanationNode{Value: 1, Description: "function score, product of:", Details: []explanationNode{
+				termExplanation("name", "pokhara", 2.2, 4, 1, 1, 2),
+				{Value: 0.5, Description: "min of:", Details: []explanationNode{
+					{Value: 0.5, Description: "Function for field osm_last_modified:"},
+					{Value: 3.4e38, Description: "maxBoost"},
+				}},
+			}},
+			query: "pokhara",
+			want:  perfectTermValue(2.2, 4, 1, 2) * 0.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terms := splitQuery(tt.query)
+			if got := perfectScore(tt.node, terms); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("perfectScore = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyMatchConfidence(t *testing.T) {
+	// A partial match in a long field scores well below the perfect document
+	top := termExplanation("name", "kathmandu", 2.2, 3, 1, 4, 2)
+	maxScore := perfectScore(top, splitQuery("kathmandu"))
+	if top.Value >= maxScore {
+		t.Fatalf("top score %v should be below the perfect score %v", top.Value, maxScore)
+	}
+
+	hits := []ESHit{{Score: top.Value}, {Score: 2 * maxScore}}
+	results := []*model.Location{{Name: "Kathmandu"}, {Name: "Kathmandu"}}
+	applyMatchConfidence("kathmandu", results, hits, maxScore)
+
+	if got, want := *results[0].MatchConfidence, top.Value/maxScore; math.Abs(got-want) > 1e-9 {
+		t.Errorf("confidence = %v, want %v", got, want)
+	}
+	if got := *results[1].MatchConfidence; got != 1 {
+		t.Errorf("confidence above the perfect score = %v, want capped at 1", got)
+	}
+
+	unknown := []*model.Location{{Name: "Kathmandu"}}
+	applyMatchConfidence("kathmandu", unknown, hits[:1], 0)
+	if unknown[0].MatchConfidence != nil {
+		t.Errorf("confidence without a maximum score = %v, want nil", *unknown[0].MatchConfidence)
+	}
+}
+
+func TestPerfectScoreFromJSON(t *testing.T) {
+	raw, err := json.Marshal(termExplanation("name", "lalitpur", 2.2, 2.5, 1, 1, 1.8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var root explanationNode
+	if err := json.Unmarshal(raw, &root); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got, want := perfectScore(root, splitQuery("lalitpur")), root.Value; math.Abs(got-want) > 1e-9 {
+		t.Errorf("perfectScore of an exact one-term match = %v, want its own score %v", got, want)
+	}
+}
+
+// searchLocationContext returns a context resolving searchLocation with the
+// results sub-selection selections
+func searchLocationContext(ctx context.Context, selections ...ast.Selection) context.Context {
+	results := &ast.Field{Name: "results", SelectionSet: selections}
+	return graphql.WithFieldContext(ctx, &graphql.FieldContext{
+		Field: graphql.CollectedField{
+			Field:      &ast.Field{Name: "searchLocation"},
+			Selections: ast.SelectionSet{&ast.Field{Name: "total"}, results},
+		},
+	})
+}
+
+func TestSelectsField(t *testing.T) {
+	fragment := &ast.FragmentSpread{Name: "scores", Definition: &ast.FragmentDefinition{
+		SelectionSet: ast.SelectionSet{&ast.Field{Name: "score"}, &ast.Field{Name: "matchConfidence"}},
+	}}
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want bool
+	}{
+		{"outside a GraphQL request", context.Background(), false},
+		{"not selected", searchLocationContext(context.Background(), &ast.Field{Name: "name"}), false},
+		{"nested field", searchLocationContext(context.Background(), &ast.Field{Name: "matchConfidence"}), true},
+		{"fragment spread", searchLocationContext(context.Background(), fragment), true},
+		{"inline fragment", searchLocationContext(context.Background(), &ast.InlineFragment{
+			SelectionSet: ast.SelectionSet{&ast.Field{Name: "matchConfidence"}},
+		}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectsField(tt.ctx, "matchConfidence"); got != tt.want {
+				t.Errorf("selectsField = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// confidenceHits answers searches with one hit and explains it with a
+// single-term explanation
+func confidenceHits(req esRequest) (int, string) {
+	if strings.Contains(req.path, "/_explain/") {
+		explanation, _ := json.Marshal(termExplanation("name", "kathmandu", 2.2, 3, 1, 4, 2))
+		return http.StatusOK, `{"matched":true,"explanation":` + string(explanation) + `}`
+	}
+	return http.StatusOK, `{"hits":{"total":{"value":1},"max_score":1.5,"hits":[{"_id":"admin_1","_score":1.5,"_source":{"name":"Kathmandu"}}]}}`
+}
+
+func TestExecuteSearchExplainsOnlyForSelectedConfidence(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		wantRequests int
+	}{
+		{"not selected", searchLocationContext(t.Context(), &ast.Field{Name: "name"}), 1},
+		{"selected", searchLocationContext(t.Context(), &ast.Field{Name: "matchConfidence"}), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeES{respond: confidenceHits}
+			r := &queryResolver{newFakeESResolver(t, fake)}
+
+			response, err := r.executeSearch(tt.ctx, model.LocationSearchInput{Query: "kathmandu"})
+			if err != nil {
+				t.Fatalf("executeSearch: %v", err)
+			}
+			if len(fake.requests) != tt.wantRequests {
+				t.Fatalf("got %d requests, want %d: %+v", len(fake.requests), tt.wantRequests, fake.requests)
+			}
+			if gotConfidence := response.Results[0].MatchConfidence != nil; gotConfidence != (tt.wantRequests == 2) {
+				t.Errorf("matchConfidence set = %v with %d requests", gotConfidence, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestMaxPossibleScoreReusesTopExplanation(t *testing.T) {
+	fake := &fakeES{respond: confidenceHits}
+	r := &queryResolver{newFakeESResolver(t, fake)}
+
+	top := termExplanation("name", "kathmandu", 2.2, 3, 1, 4, 2)
+	explanation, _ := json.Marshal(top)
+	hits := []ESHit{{ID: "admin_1", Score: top.Value}}
+
+	got := r.maxPossibleScore(t.Context(), model.LocationSearchInput{Query: "kathmandu"}, 10, hits, explanation)
+	if want := perfectScore(top, splitQuery("kathmandu")); math.Abs(got-want) > 1e-9 {
+		t.Errorf("maxPossibleScore = %v, want %v", got, want)
+	}
+	if len(fake.requests) != 0 {
+		t.Errorf("got %d requests, want the explainTop explanation reused", len(fake.requests))
+	}
+}
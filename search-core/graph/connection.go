@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"context"
+
+	"search-core/graph/model"
+)
+
+// SearchLocationConnection runs SearchLocation and returns the page as a
+// Relay-style connection. first and after take precedence over the input's
+// limit and after.
+func (r *queryResolver) SearchLocationConnection(ctx context.Context, input model.LocationSearchInput, first *int, after *string) (*model.SearchLocationConnection, error) {
+	if first != nil {
+		input.Limit = first
+	}
+	if after != nil {
+		input.After = after
+	}
+
+	response, err := r.SearchLocation(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	edges := make([]*model.LocationEdge, 0, len(response.Results))
+	for _, loc := range response.Results {
+		cursor := ""
+		if loc.Cursor != nil {
+			cursor = *loc.Cursor
+		}
+		edges = append(edges, &model.LocationEdge{Node: loc, Cursor: cursor})
+	}
+
+	pageInfo := &model.PageInfo{
+		HasNextPage:     response.NextCursor != nil,
+		HasPreviousPage: input.After != nil || (input.Offset != nil && *input.Offset > 0),
+		EndCursor:       response.NextCursor,
+	}
+	if len(edges) > 0 && edges[0].Cursor != "" {
+		pageInfo.StartCursor = &edges[0].Cursor
+	}
+
+	return &model.SearchLocationConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo,
+		TotalCount: response.Total,
+	}, nil
+}
@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// boundariesIndex holds official province polygons imported by the syncer
+// from PROVINCE_GEOJSON_URL
+const boundariesIndex = "nepal_boundaries"
+
+// IsInsideProvince checks whether a point lies inside a province's boundary
+// polygon using a geo_shape contains query. Official polygons in the
+// boundaries index are used when imported, alongside OSM province polygons
+// stored in the locations index.
+func (r *queryResolver) IsInsideProvince(ctx context.Context, lat float64, lon float64, province string) (bool, error) {
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return false, userError("lat must be between -90 and 90 and lon between -180 and 180")
+	}
+
+	query := map[string]interface{}{
+		"size":    0,
+		"_source": false,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []map[string]interface{}{
+					{"geo_shape": map[string]interface{}{
+						"boundary": map[string]interface{}{
+							"shape": map[string]interface{}{
+								"type":        "point",
+								"coordinates": []float64{lon, lat},
+							},
+							"relation": "contains",
+						},
+						"ignore_unmapped": true,
+					}},
+				},
+				"should": []map[string]interface{}{
+					officialProvinceClause(province),
+					osmProvinceClause(province),
+				},
+				"minimum_should_match": 1,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+		return false, fmt.Errorf("error encoding query: %w", err)
+	}
+
+	// The boundaries index only exists once an official GeoJSON import has run
+	res, err := r.ESClient.Search(
+		r.ESClient.Search.WithContext(ctx),
+		r.ESClient.Search.WithIndex(boundariesIndex, locationIndex),
+		r.ESClient.Search.WithBody(&buf),
+		r.ESClient.Search.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		return false, fmt.Errorf("error executing search: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return false, fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+
+	var esResponse ElasticsearchResponse
+	if err := json.NewDecoder(res.Body).Decode(&esResponse); err != nil {
+		return false, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	return esResponse.Hits.Total.Value > 0, nil
+}
+
+// officialProvinceClause matches the named province in the boundaries index
+func officialProvinceClause(province string) map[string]interface{} {
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"filter": []map[string]interface{}{
+				{"term": map[string]interface{}{"_index": boundariesIndex}},
+				{"multi_match": map[string]interface{}{
+					"query":  province,
+					"fields": []string{"name.keyword", "name_ne.keyword"},
+					"type":   "best_fields",
+				}},
+			},
+		},
+	}
+}
+
+// osmProvinceClause matches the named province boundary in the locations index
+func osmProvinceClause(province string) map[string]interface{} {
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"filter": []map[string]interface{}{
+				{"term": map[string]interface{}{"_index": locationIndex}},
+				{"term": map[string]interface{}{"entity_type": "admin_boundary"}},
+				{"term": map[string]interface{}{"admin_level": 4}},
+				{"multi_match": map[string]interface{}{
+					"query":  province,
+					"fields": []string{"province.keyword", "province_ne.keyword", "name.keyword", "name_en.keyword"},
+					"type":   "best_fields",
+				}},
+			},
+		},
+	}
+}
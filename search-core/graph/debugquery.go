@@ -0,0 +1,32 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"search-core/graph/model"
+	"search-core/internal/normalize"
+)
+
+// DebugQuery returns the Elasticsearch request body a search would send, as
+// indented JSON, without running it. Requires ENABLE_DEBUG_FEATURES=true.
+func (r *queryResolver) DebugQuery(ctx context.Context, input model.LocationSearchInput) (string, error) {
+	if !r.DebugFeatures {
+		return "", forbiddenError("debugQuery requires ENABLE_DEBUG_FEATURES=true")
+	}
+
+	input.Query = normalize.SanitizeQuery(input.Query)
+	input = r.normalizeInput(input)
+
+	query, err := r.prepareQuery(input, resolveLimit(input.Limit))
+	if err != nil {
+		return "", err
+	}
+
+	body, err := json.MarshalIndent(query, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error encoding query: %w", err)
+	}
+	return string(body), nil
+}
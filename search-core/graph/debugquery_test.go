@@ -0,0 +1,49 @@
+package graph
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"search-core/graph/model"
+)
+
+func TestDebugQuery(t *testing.T) {
+	fake := &fakeES{respond: func(req esRequest) (int, string) {
+		return http.StatusInternalServerError, `{}`
+	}}
+	resolver := newFakeESResolver(t, fake)
+	resolver.DebugFeatures = true
+	r := &queryResolver{resolver}
+
+	got, err := r.DebugQuery(t.Context(), model.LocationSearchInput{Query: "Patan ward 5", District: strPtr("Lalitpur")})
+	if err != nil {
+		t.Fatalf("DebugQuery: %v", err)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(got), &body); err != nil {
+		t.Fatalf("DebugQuery returned invalid JSON: %v\n%s", err, got)
+	}
+	for _, key := range []string{"query", "size", "sort"} {
+		if _, ok := body[key]; !ok {
+			t.Errorf("query body has no %q key:\n%s", key, got)
+		}
+	}
+	if len(fake.requests) != 0 {
+		t.Errorf("DebugQuery sent %d Elasticsearch request(s), want none", len(fake.requests))
+	}
+}
+
+func TestDebugQueryDisabled(t *testing.T) {
+	r := &queryResolver{&Resolver{}}
+	_, err := r.DebugQuery(t.Context(), model.LocationSearchInput{Query: "Patan"})
+
+	var gqlErr *gqlerror.Error
+	if !errors.As(err, &gqlErr) || gqlErr.Extensions["code"] != "FORBIDDEN" {
+		t.Fatalf("error = %v, want a FORBIDDEN error", err)
+	}
+}
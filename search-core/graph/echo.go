@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"strings"
+
+	"search-core/graph/model"
+)
+
+// echoInput reports the normalized search input actually sent to
+// Elasticsearch, with defaults filled in
+func echoInput(input model.LocationSearchInput) *model.LocationSearchInputEcho {
+	sortBy := model.LocationSortModeRelevance
+	if input.SortBy != nil {
+		sortBy = *input.SortBy
+	}
+	searchMode := model.SearchModeFuzzy
+	if input.SearchMode != nil {
+		searchMode = *input.SearchMode
+	}
+
+	return &model.LocationSearchInputEcho{
+		Query:          input.Query,
+		Ward:           input.Ward,
+		Wards:          input.Wards,
+		Municipality:   input.Municipality,
+		Municipalities: input.Municipalities,
+		District:       input.District,
+		Province:       input.Province,
+		ProvinceNumber: input.ProvinceNumber,
+		OsmID:          input.OsmID,
+		EntityType:     input.EntityType,
+		Country:        input.Country,
+		Limit:          resolveLimit(input.Limit),
+		Offset:         input.Offset,
+		SortBy:         sortBy,
+		SearchMode:     searchMode,
+	}
+}
+
+// trimFilter trims surrounding whitespace from an optional text filter
+func trimFilter(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	return strPtr(strings.TrimSpace(*s))
+}
+
+// trimFilters trims a list of text filters, dropping empty entries
+func trimFilters(values []string) []string {
+	var trimmed []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			trimmed = append(trimmed, v)
+		}
+	}
+	return trimmed
+}
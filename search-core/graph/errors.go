@@ -0,0 +1,27 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// forbiddenError reports a request for an operation the caller may not perform
+func forbiddenError(message string) error {
+	return &gqlerror.Error{
+		Message: message,
+		Extensions: map[string]interface{}{
+			"code": "FORBIDDEN",
+		},
+	}
+}
+
+// userError reports invalid client input as a GraphQL error with a BAD_USER_INPUT code
+func userError(format string, args ...interface{}) error {
+	return &gqlerror.Error{
+		Message: fmt.Sprintf(format, args...),
+		Extensions: map[string]interface{}{
+			"code": "BAD_USER_INPUT",
+		},
+	}
+}
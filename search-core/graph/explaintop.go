@@ -0,0 +1,83 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+
+	"search-core/graph/model"
+)
+
+// topDominanceRatio is how many times the second result's score the top
+// result must reach before explainTop fetches its explanation
+const topDominanceRatio = 10
+
+// explainTopResult returns the score explanation for the top hit when it
+// dominates the second hit, or nil otherwise. Only that one document is
+// explained, which is far cheaper than explain mode on every hit.
+func (r *queryResolver) explainTopResult(ctx context.Context, input model.LocationSearchInput, limit int, hits []ESHit) (json.RawMessage, error) {
+	if len(hits) < 2 || hits[1].Score <= 0 || hits[0].Score <= topDominanceRatio*hits[1].Score {
+		return nil, nil
+	}
+	return r.explainHit(ctx, r.withStalenessDecay(r.buildSearchQuery(input, limit)["query"]), hits[0])
+}
+
+// explainHit returns Elasticsearch's explanation of the score query gives hit
+func (r *Resolver) explainHit(ctx context.Context, query interface{}, hit ESHit) (json.RawMessage, error) {
+	body := map[string]interface{}{"query": query}
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		return nil, fmt.Errorf("error encoding query: %w", err)
+	}
+
+	opts := []func(*esapi.ExplainRequest){
+		r.ESClient.Explain.WithContext(ctx),
+		r.ESClient.Explain.WithBody(&buf),
+	}
+	if hit.Routing != "" {
+		opts = append(opts, r.ESClient.Explain.WithRouting(hit.Routing))
+	}
+
+	res, err := r.ESClient.Explain(locationIndex, hit.ID, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing explain: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+
+	var esResponse struct {
+		Explanation json.RawMessage `json:"explanation"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&esResponse); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+	return esResponse.Explanation, nil
+}
+
+// topResultExplanation runs explainTopResult when the input asks for it and
+// full explain mode is off. Failures are logged, never returned, since the
+// explanation is only a debugging aid.
+func (r *queryResolver) topResultExplanation(ctx context.Context, input model.LocationSearchInput, limit int, hits []ESHit) json.RawMessage {
+	if !r.DebugFeatures || input.ExplainTop == nil || !*input.ExplainTop {
+		return nil
+	}
+	if input.Explain != nil && *input.Explain {
+		return nil
+	}
+
+	explanation, err := r.explainTopResult(ctx, input, limit, hits)
+	if err != nil {
+		log.Printf("WARNING: failed to explain top result: %v", err)
+		return nil
+	}
+	return explanation
+}
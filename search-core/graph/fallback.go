@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"context"
+
+	"search-core/graph/model"
+)
+
+// relaxableFilter is a search filter that fallback search may drop
+type relaxableFilter struct {
+	name  string
+	clear func(input *model.LocationSearchInput) bool
+}
+
+// relaxOrder lists filters from narrowest to broadest, in the order fallback
+// search drops them
+var relaxOrder = []relaxableFilter{
+	{"ward", func(input *model.LocationSearchInput) bool {
+		set := input.Ward != nil
+		input.Ward = nil
+		return set
+	}},
+	{"wards", func(input *model.LocationSearchInput) bool {
+		set := len(input.Wards) > 0
+		input.Wards = nil
+		return set
+	}},
+	{"municipality", func(input *model.LocationSearchInput) bool {
+		set := input.Municipality != nil && *input.Municipality != ""
+		input.Municipality = nil
+		return set
+	}},
+	{"municipalities", func(input *model.LocationSearchInput) bool {
+		set := len(input.Municipalities) > 0
+		input.Municipalities = nil
+		return set
+	}},
+	{"district", func(input *model.LocationSearchInput) bool {
+		set := input.District != nil && *input.District != ""
+		input.District = nil
+		return set
+	}},
+	{"province", func(input *model.LocationSearchInput) bool {
+		set := input.Province != nil && *input.Province != ""
+		input.Province = nil
+		return set
+	}},
+	{"provinceNumber", func(input *model.LocationSearchInput) bool {
+		set := input.ProvinceNumber != nil
+		input.ProvinceNumber = nil
+		return set
+	}},
+}
+
+// searchWithFallback retries a search that found nothing, dropping one filter
+// at a time until results are found or no filters remain. input is updated to
+// the filters of the last search run; the dropped filter names are returned.
+func (r *queryResolver) searchWithFallback(ctx context.Context, input *model.LocationSearchInput, limit int, esResponse *ElasticsearchResponse) (*ElasticsearchResponse, []string, error) {
+	var relaxed []string
+	for _, filter := range relaxOrder {
+		if esResponse.Hits.Total.Value > 0 {
+			break
+		}
+		if !filter.clear(input) {
+			continue
+		}
+		relaxed = append(relaxed, filter.name)
+
+		query, err := r.prepareQuery(*input, limit)
+		if err != nil {
+			return nil, nil, err
+		}
+		esResponse, err = r.searchRouted(ctx, query, r.provinceRouting(*input))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return esResponse, relaxed, nil
+}
@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"sort"
+
+	"search-core/graph/model"
+)
+
+// locationSourceFields maps maskable Location fields to their ES source fields.
+// id and score come from hit metadata and are always returned.
+var locationSourceFields = map[string]string{
+	"entityType":       "entity_type",
+	"name":             "name",
+	"nameNe":           "name_ne",
+	"nameEn":           "name_en",
+	"placeType":        "place_type",
+	"adminLevel":       "admin_level",
+	"location":         "location",
+	"ward":             "ward",
+	"municipality":     "municipality",
+	"municipalityNe":   "municipality_ne",
+	"municipalityType": "municipality_type",
+	"district":         "district",
+	"districtNe":       "district_ne",
+	"province":         "province",
+	"provinceNe":       "province_ne",
+	"provinceNumber":   "province_number",
+	"country":          "country",
+	"lastUpdated":      "last_updated",
+	"matchedTags":      "tags",
+	"osmId":            "osm_id",
+}
+
+// maskAlwaysFetched are source fields needed server-side for ranking, match
+// confidence, parent validation and neighbor lookup, fetched even when not requested
+var maskAlwaysFetched = []string{"name", "name_ne", "name_en", "entity_type", "admin_level", "ward", "municipality", "district", "province", "boost_score"}
+
+// sourceIncludes validates a field mask and returns the ES source fields to fetch
+func sourceIncludes(fields []string) ([]string, error) {
+	includes := map[string]bool{}
+	for _, field := range maskAlwaysFetched {
+		includes[field] = true
+	}
+	for _, field := range fields {
+		source, ok := locationSourceFields[field]
+		if !ok {
+			return nil, userError("Unknown field in fields mask: %s", field)
+		}
+		includes[source] = true
+	}
+
+	result := make([]string, 0, len(includes))
+	for field := range includes {
+		result = append(result, field)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// maskLocation clears optional fields that were not requested
+func maskLocation(loc *model.Location, fields []string) {
+	keep := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		keep[field] = true
+	}
+
+	if !keep["nameNe"] {
+		loc.NameNe = nil
+	}
+	if !keep["nameEn"] {
+		loc.NameEn = nil
+	}
+	if !keep["placeType"] {
+		loc.PlaceType = nil
+	}
+	if !keep["adminLevel"] {
+		loc.AdminLevel = nil
+	}
+	if !keep["location"] {
+		loc.Location = nil
+	}
+	if !keep["ward"] {
+		loc.Ward = nil
+	}
+	if !keep["municipality"] {
+		loc.Municipality = nil
+	}
+	if !keep["municipalityNe"] {
+		loc.MunicipalityNe = nil
+	}
+	if !keep["municipalityType"] {
+		loc.MunicipalityType = nil
+	}
+	if !keep["district"] {
+		loc.District = nil
+	}
+	if !keep["districtNe"] {
+		loc.DistrictNe = nil
+	}
+	if !keep["province"] {
+		loc.Province = nil
+	}
+	if !keep["provinceNe"] {
+		loc.ProvinceNe = nil
+	}
+	if !keep["provinceNumber"] {
+		loc.ProvinceNumber = nil
+	}
+	if !keep["lastUpdated"] {
+		loc.LastUpdated = nil
+	}
+	if !keep["osmId"] {
+		loc.OsmID = nil
+	}
+	if !keep["matchedTags"] {
+		loc.MatchedTags = nil
+	}
+}
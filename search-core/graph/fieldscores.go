@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"search-core/graph/model"
+)
+
+// explanationNode is one node of an Elasticsearch score explanation
+type explanationNode struct {
+	Value       float64           `json:"value"`
+	Description string            `json:"description"`
+	Details     []explanationNode `json:"details"`
+}
+
+// showFieldScores reports whether per-field score contributions were requested and allowed
+func (r *Resolver) showFieldScores(input model.LocationSearchInput) bool {
+	return r.DebugFeatures && input.ShowFieldScores != nil && *input.ShowFieldScores
+}
+
+// fieldScores breaks a hit's score explanation down into the contribution of
+// each matched field. Only the best field of a best_fields match counts, as
+// in the score itself. When results are re-ranked, the boost_score multiplier
+// is reported as well.
+func fieldScores(hit ESHit, reranked bool) []*model.FieldScore {
+	var root explanationNode
+	if len(hit.Explanation) == 0 || json.Unmarshal(hit.Explanation, &root) != nil {
+		return nil
+	}
+
+	totals := map[string]float64{}
+	collectFieldScores(root, totals)
+
+	scores := make([]*model.FieldScore, 0, len(totals)+1)
+	for field, score := range totals {
+		scores = append(scores, &model.FieldScore{Field: field, Score: score})
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].Field < scores[j].Field
+	})
+
+	if reranked && hit.Source.BoostScore > 0 {
+		scores = append(scores, &model.FieldScore{Field: "boost_score", Score: hit.Source.BoostScore})
+	}
+	return scores
+}
+
+// collectFieldScores adds the term weights under node to totals by field
+func collectFieldScores(node explanationNode, totals map[string]float64) {
+	// Lucene describes term scores as "weight(field:term in doc) ..." and
+	// multi-term matches as "weight(Synonym(field:a field:b) in doc) ..."
+	if rest, ok := strings.CutPrefix(node.Description, "weight("); ok {
+		rest = strings.TrimPrefix(rest, "Synonym(")
+		if field, _, ok := strings.Cut(rest, ":"); ok {
+			totals[field] += node.Value
+		}
+		return
+	}
+
+	// A dis_max (best_fields) scores only its best clause
+	if strings.HasPrefix(node.Description, "max of:") {
+		var best *explanationNode
+		for i := range node.Details {
+			if best == nil || node.Details[i].Value > best.Value {
+				best = &node.Details[i]
+			}
+		}
+		if best != nil {
+			collectFieldScores(*best, totals)
+		}
+		return
+	}
+
+	for _, detail := range node.Details {
+		collectFieldScores(detail, totals)
+	}
+}
@@ -0,0 +1,109 @@
+package graph
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"sort"
+	"strings"
+
+	"search-core/graph/model"
+)
+
+// fallbackLocationsJSON is a static gazetteer of Nepal's provinces and
+// most-searched locations, used when Elasticsearch is unreachable. Regenerate
+// it from the search logs with scripts/generate_fallback_gazetteer.py, which
+// writes 1,000 locations: the provinces, the most frequent top results and,
+// when the logs are short, the highest-boost locations.
+//
+//go:embed nepal_locations_fallback.json
+var fallbackLocationsJSON []byte
+
+// gazetteerEntry is a fallback location in the same shape as an indexed document
+type gazetteerEntry struct {
+	ID string `json:"id"`
+	ESSource
+}
+
+var gazetteer = loadGazetteer(fallbackLocationsJSON)
+
+func loadGazetteer(data []byte) []gazetteerEntry {
+	var entries []gazetteerEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		log.Fatalf("Error parsing nepal_locations_fallback.json: %v", err)
+	}
+	return entries
+}
+
+// isUnavailable reports whether err means Elasticsearch could not be reached,
+// as opposed to rejecting the request
+func isUnavailable(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// gazetteerSearch matches the query against the fallback gazetteer's names,
+// ranking exact matches above prefix matches above substring matches. Parent
+// filters are applied; other search options are ignored.
+func gazetteerSearch(input model.LocationSearchInput) *model.LocationSearchResponse {
+	query := strings.ToLower(strings.TrimSpace(input.Query))
+
+	var hits []ESHit
+	for _, entry := range gazetteer {
+		score := gazetteerScore(query, entry.ESSource)
+		if score == 0 || !gazetteerFiltersMatch(input, entry.ESSource) {
+			continue
+		}
+		hits = append(hits, ESHit{ID: entry.ID, Score: score, Source: entry.ESSource})
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+
+	total := len(hits)
+	if limit := resolveLimit(input.Limit); len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return &model.LocationSearchResponse{
+		Results:  convertHits(hits),
+		Total:    total,
+		Clusters: []*model.GeohashCluster{},
+
+		QueryInterpretation: strPtr("Static fallback gazetteer (search index unavailable)"),
+	}
+}
+
+// gazetteerScore scores how well query matches any of a location's names
+func gazetteerScore(query string, src ESSource) float64 {
+	best := 0.0
+	for _, name := range []string{src.Name, src.NameNe, src.NameEn} {
+		name = strings.ToLower(name)
+		switch {
+		case name == "":
+		case name == query:
+			return 1
+		case strings.HasPrefix(name, query):
+			best = max(best, 0.5)
+		case strings.Contains(name, query):
+			best = max(best, 0.25)
+		}
+	}
+	return best
+}
+
+// gazetteerFiltersMatch applies the input's parent filters to a fallback location
+func gazetteerFiltersMatch(input model.LocationSearchInput, src ESSource) bool {
+	if input.District != nil && *input.District != "" &&
+		!stringsMatch(*input.District, src.District) && !stringsMatch(*input.District, src.DistrictNe) {
+		return false
+	}
+	if input.Province != nil && *input.Province != "" &&
+		!stringsMatch(*input.Province, src.Province) && !stringsMatch(*input.Province, src.ProvinceNe) {
+		return false
+	}
+	if input.ProvinceNumber != nil && *input.ProvinceNumber != src.ProvinceNumber {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+type responseHeadersKey struct{}
+
+// responseHeaders lets resolvers add HTTP response headers. gqlgen resolves
+// top-level fields concurrently, so writes are serialized.
+type responseHeaders struct {
+	mu     sync.Mutex
+	header http.Header
+}
+
+// WithResponseHeaders returns a context through which resolvers can add
+// headers to header before the response is written
+func WithResponseHeaders(ctx context.Context, header http.Header) context.Context {
+	return context.WithValue(ctx, responseHeadersKey{}, &responseHeaders{header: header})
+}
+
+// addResponseHeader adds a response header; it is a no-op outside an HTTP request
+func addResponseHeader(ctx context.Context, key, value string) {
+	h, ok := ctx.Value(responseHeadersKey{}).(*responseHeaders)
+	if !ok {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header.Add(key, value)
+}
+
+// setResponseHeader sets a response header, replacing any earlier value; it is
+// a no-op outside an HTTP request
+func setResponseHeader(ctx context.Context, key, value string) {
+	h, ok := ctx.Value(responseHeadersKey{}).(*responseHeaders)
+	if !ok {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header.Set(key, value)
+}
@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"context"
+	"strings"
+
+	"search-core/graph/model"
+)
+
+// GetDistrictHeadquarters returns the headquarters of the named district, as
+// marked by the syncer from the district's admin_centre or capital=6 tags, or
+// nil if it is not indexed
+func (r *queryResolver) GetDistrictHeadquarters(ctx context.Context, district string) (*model.Location, error) {
+	if strings.TrimSpace(district) == "" {
+		return nil, userError("district is required")
+	}
+
+	query := map[string]interface{}{
+		"size": 1,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []map[string]interface{}{
+					exactNameClause(district, "district.keyword", "district_ne.keyword"),
+					{"term": map[string]interface{}{"is_district_hq": true}},
+				},
+				"must_not": []map[string]interface{}{notDeletedClause},
+			},
+		},
+	}
+
+	esResponse, err := r.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(esResponse.Hits.Hits) == 0 {
+		return nil, nil
+	}
+	return convertToLocation(esResponse.Hits.Hits[0]), nil
+}
@@ -0,0 +1,286 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+
+	"search-core/graph/model"
+)
+
+// hierarchyCheck describes how to verify one parent field: the admin boundary
+// named by field (at adminLevel) determines the correct value of parentField
+type hierarchyCheck struct {
+	field       string
+	adminLevel  int
+	parentField string
+}
+
+// Checked bottom-up so a corrected district is used when checking the province
+var hierarchyChecks = []hierarchyCheck{
+	{field: "municipality", adminLevel: 7, parentField: "district"},
+	{field: "district", adminLevel: 6, parentField: "province"},
+}
+
+// ValidateHierarchy checks a location's parents against the indexed admin boundaries and patches mismatches
+func (r *mutationResolver) ValidateHierarchy(ctx context.Context, id string) (*model.ValidationCorrectionResult, error) {
+	hit, err := r.getLocation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if hit == nil {
+		return nil, nil
+	}
+
+	src := hit.Source
+	patch := map[string]interface{}{}
+	changes := []string{}
+
+	for _, check := range hierarchyChecks {
+		name, _ := adminField(&src, check.field)
+		if name == "" {
+			continue
+		}
+
+		parent, err := r.findAdminBoundary(ctx, check.adminLevel, check.field, name)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil || parent.ID == id {
+			continue
+		}
+
+		expected, expectedNe := adminField(&parent.Source, check.parentField)
+		actual, _ := adminField(&src, check.parentField)
+		if expected == "" || stringsMatch(expected, actual) {
+			continue
+		}
+
+		setAdminField(&src, check.parentField, expected, expectedNe)
+		patch[check.parentField] = expected
+		patch[check.parentField+"_ne"] = expectedNe
+		if check.parentField == "province" {
+			// provinceNumber filters must follow the corrected province; the
+			// syncer indexes null for names it cannot map
+			src.ProvinceNumber = provinceNumber(expected)
+			patch["province_number"] = optionalInt(src.ProvinceNumber)
+		}
+		changes = append(changes, fmt.Sprintf("%s: %q -> %q", check.parentField, actual, expected))
+	}
+
+	if len(patch) > 0 {
+		// Documents are routed by province, so a corrected province moves the document
+		routing := hit.Routing
+		if r.RoutingOptimization {
+			routing = provinceRoutingKey(src.Province)
+		}
+		if routing != hit.Routing {
+			err = r.moveLocation(ctx, id, hit.Routing, routing, patch)
+		} else {
+			err = r.updateLocation(ctx, id, hit.Routing, patch)
+		}
+		if err != nil {
+			return nil, err
+		}
+		// The update has been applied, so a lost snapshot is logged rather than failing the mutation
+		if err := r.saveSnapshot(ctx, id, hit.Source); err != nil {
+			log.Printf("WARNING: failed to save history snapshot for %s: %v", id, err)
+		}
+		r.recordAudit(ctx, "validateHierarchy", id, hit.Source, src)
+	}
+
+	return &model.ValidationCorrectionResult{
+		ID:        id,
+		Corrected: len(patch) > 0,
+		Changes:   changes,
+	}, nil
+}
+
+// findAdminBoundary returns the admin boundary at the given level whose field matches name
+func (r *Resolver) findAdminBoundary(ctx context.Context, adminLevel int, field, name string) (*ESHit, error) {
+	query := map[string]interface{}{
+		"size": 1,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []map[string]interface{}{
+					{"term": map[string]interface{}{"entity_type": "admin_boundary"}},
+					{"term": map[string]interface{}{"admin_level": adminLevel}},
+					{"term": map[string]interface{}{field + ".keyword": name}},
+				},
+			},
+		},
+	}
+
+	esResponse, err := r.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(esResponse.Hits.Hits) == 0 {
+		return nil, nil
+	}
+	return &esResponse.Hits.Hits[0], nil
+}
+
+// updateLocation applies a partial document update to a location indexed
+// with the given routing (empty if not routed)
+func (r *Resolver) updateLocation(ctx context.Context, id, routing string, doc map[string]interface{}) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(map[string]interface{}{"doc": doc}); err != nil {
+		return fmt.Errorf("error encoding update: %w", err)
+	}
+
+	opts := []func(*esapi.UpdateRequest){r.ESClient.Update.WithContext(ctx)}
+	if routing != "" {
+		opts = append(opts, r.ESClient.Update.WithRouting(routing))
+	}
+
+	res, err := r.ESClient.Update(locationIndex, id, &buf, opts...)
+	if err != nil {
+		return fmt.Errorf("error updating location: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+	return nil
+}
+
+// moveLocation applies a partial update to a location by reindexing it under
+// newRouting, since a routed document cannot change shards in place. The old
+// copy is deleted first because both routings may resolve to the same shard;
+// if indexing the new copy fails, the original is restored.
+func (r *Resolver) moveLocation(ctx context.Context, id, oldRouting, newRouting string, doc map[string]interface{}) error {
+	source, err := r.locationSource(ctx, id, oldRouting)
+	if err != nil {
+		return err
+	}
+	updated := maps.Clone(source)
+	maps.Copy(updated, doc)
+
+	if err := r.deleteLocationDocument(ctx, id, oldRouting); err != nil {
+		return err
+	}
+	if err := r.indexLocationDocument(ctx, id, newRouting, updated); err != nil {
+		if restoreErr := r.indexLocationDocument(ctx, id, oldRouting, source); restoreErr != nil {
+			log.Printf("WARNING: failed to restore %s after a failed reindex: %v", id, restoreErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// locationSource returns the full stored document of a location indexed with
+// the given routing, including fields ESSource does not model
+func (r *Resolver) locationSource(ctx context.Context, id, routing string) (map[string]interface{}, error) {
+	opts := []func(*esapi.GetRequest){r.ESClient.Get.WithContext(ctx)}
+	if routing != "" {
+		opts = append(opts, r.ESClient.Get.WithRouting(routing))
+	}
+
+	res, err := r.ESClient.Get(locationIndex, id, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching location: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+
+	var doc struct {
+		Source map[string]interface{} `json:"_source"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+	return doc.Source, nil
+}
+
+// deleteLocationDocument removes a location indexed with the given routing
+func (r *Resolver) deleteLocationDocument(ctx context.Context, id, routing string) error {
+	opts := []func(*esapi.DeleteRequest){r.ESClient.Delete.WithContext(ctx)}
+	if routing != "" {
+		opts = append(opts, r.ESClient.Delete.WithRouting(routing))
+	}
+
+	res, err := r.ESClient.Delete(locationIndex, id, opts...)
+	if err != nil {
+		return fmt.Errorf("error deleting location: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+	return nil
+}
+
+// indexLocationDocument stores a full location document under the given routing
+func (r *Resolver) indexLocationDocument(ctx context.Context, id, routing string, source map[string]interface{}) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(source); err != nil {
+		return fmt.Errorf("error encoding location: %w", err)
+	}
+
+	opts := []func(*esapi.IndexRequest){
+		r.ESClient.Index.WithContext(ctx),
+		r.ESClient.Index.WithDocumentID(id),
+	}
+	if routing != "" {
+		opts = append(opts, r.ESClient.Index.WithRouting(routing))
+	}
+
+	res, err := r.ESClient.Index(locationIndex, &buf, opts...)
+	if err != nil {
+		return fmt.Errorf("error indexing location: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+	return nil
+}
+
+// provinceRoutingKey returns the routing key the syncer indexes a document
+// in province under; empty means the document is not routed
+func provinceRoutingKey(province string) string {
+	return strings.ToLower(strings.TrimSpace(province))
+}
+
+// adminField returns the English and Nepali values of an admin hierarchy field
+func adminField(src *ESSource, field string) (string, string) {
+	switch field {
+	case "municipality":
+		return src.Municipality, src.MunicipalityNe
+	case "district":
+		return src.District, src.DistrictNe
+	case "province":
+		return src.Province, src.ProvinceNe
+	}
+	return "", ""
+}
+
+// setAdminField sets the English and Nepali values of an admin hierarchy field
+func setAdminField(src *ESSource, field, value, valueNe string) {
+	switch field {
+	case "municipality":
+		src.Municipality, src.MunicipalityNe = value, valueNe
+	case "district":
+		src.District, src.DistrictNe = value, valueNe
+	case "province":
+		src.Province, src.ProvinceNe = value, valueNe
+	}
+}
@@ -0,0 +1,215 @@
+package graph
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	elasticsearch "github.com/elastic/go-elasticsearch/v8"
+)
+
+// esRequest is one request sent to a fakeES transport
+type esRequest struct {
+	method  string
+	path    string
+	routing string
+	body    string
+}
+
+// fakeES records every Elasticsearch request and answers it with the status
+// and body returned by respond
+type fakeES struct {
+	requests []esRequest
+	respond  func(req esRequest) (int, string)
+}
+
+func (f *fakeES) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded := esRequest{method: req.Method, path: req.URL.Path, routing: req.URL.Query().Get("routing")}
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		recorded.body = string(body)
+	}
+	f.requests = append(f.requests, recorded)
+
+	status, body := f.respond(recorded)
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"X-Elastic-Product": []string{"Elasticsearch"}, "Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// newFakeESResolver returns a resolver whose Elasticsearch client talks to fake
+func newFakeESResolver(t *testing.T, fake *fakeES) *Resolver {
+	t.Helper()
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: []string{"http://elasticsearch:9200"},
+		Transport: fake,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return &Resolver{ESClient: client, RoutingOptimization: true}
+}
+
+const storedLocation = `{"_id":"way_1","_routing":"gandaki","_source":{"name":"Patan","province":"Gandaki","district":"Lalitpur","search_text":"patan lalitpur"}}`
+
+func TestMoveLocation(t *testing.T) {
+	fake := &fakeES{respond: func(req esRequest) (int, string) {
+		if req.method == http.MethodGet {
+			return http.StatusOK, storedLocation
+		}
+		return http.StatusOK, `{"result":"ok"}`
+	}}
+	r := newFakeESResolver(t, fake)
+
+	patch := map[string]interface{}{"province": "Bagmati"}
+	if err := r.moveLocation(t.Context(), "way_1", "gandaki", provinceRoutingKey(" Bagmati "), patch); err != nil {
+		t.Fatalf("moveLocation: %v", err)
+	}
+
+	want := []struct {
+		method  string
+		routing string
+	}{
+		{http.MethodGet, "gandaki"},
+		{http.MethodDelete, "gandaki"},
+		{http.MethodPut, "bagmati"},
+	}
+	if len(fake.requests) != len(want) {
+		t.Fatalf("got %d requests, want %d: %+v", len(fake.requests), len(want), fake.requests)
+	}
+	for i, w := range want {
+		if got := fake.requests[i]; got.method != w.method || got.routing != w.routing {
+			t.Errorf("request %d = %s routing %q, want %s routing %q", i, got.method, got.routing, w.method, w.routing)
+		}
+	}
+
+	var indexed map[string]interface{}
+	if err := json.Unmarshal([]byte(fake.requests[2].body), &indexed); err != nil {
+		t.Fatalf("unmarshal indexed document: %v", err)
+	}
+	if indexed["province"] != "Bagmati" {
+		t.Errorf("province = %v, want Bagmati", indexed["province"])
+	}
+	if indexed["search_text"] != "patan lalitpur" {
+		t.Errorf("search_text = %v, want the stored field kept", indexed["search_text"])
+	}
+}
+
+func TestMoveLocationRestoresOnIndexFailure(t *testing.T) {
+	fake := &fakeES{}
+	fake.respond = func(req esRequest) (int, string) {
+		switch {
+		case req.method == http.MethodGet:
+			return http.StatusOK, storedLocation
+		case req.method == http.MethodPut && req.routing == "bagmati":
+			return http.StatusServiceUnavailable, `{"error":"unavailable"}`
+		}
+		return http.StatusOK, `{"result":"ok"}`
+	}
+	r := newFakeESResolver(t, fake)
+
+	err := r.moveLocation(t.Context(), "way_1", "gandaki", "bagmati", map[string]interface{}{"province": "Bagmati"})
+	if err == nil {
+		t.Fatal("moveLocation succeeded, want the index error")
+	}
+
+	last := fake.requests[len(fake.requests)-1]
+	if last.method != http.MethodPut || last.routing != "gandaki" {
+		t.Fatalf("last request = %s routing %q, want the original restored under gandaki", last.method, last.routing)
+	}
+	if !strings.Contains(last.body, `"province":"Gandaki"`) {
+		t.Errorf("restored document = %s, want the original province", last.body)
+	}
+}
+
+func TestProvinceNumber(t *testing.T) {
+	tests := map[string]int{
+		"Bagmati":          3,
+		"Bagmati Province": 3,
+		" sudurpashchim ":  7,
+		"कोशी प्रदेश":      1,
+		"Province No. 1":   0,
+		"":                 0,
+	}
+	for name, want := range tests {
+		if got := provinceNumber(name); got != want {
+			t.Errorf("provinceNumber(%q) = %d, want %d", name, got, want)
+		}
+	}
+}
+
+// misplacedLocation is indexed under Gandaki although its district is in Bagmati
+const misplacedLocation = `{"_id":"way_1","_routing":"gandaki","_source":{"name":"Patan","district":"Lalitpur","province":"Gandaki","province_ne":"गण्डकी","province_number":4}}`
+
+// hierarchyES answers ValidateHierarchy's requests for misplacedLocation
+func hierarchyES(req esRequest) (int, string) {
+	switch {
+	case req.method == http.MethodGet:
+		return http.StatusOK, strings.Replace(misplacedLocation, `{"_id"`, `{"found":true,"_id"`, 1)
+	case strings.Contains(req.body, `"ids"`):
+		return http.StatusOK, `{"hits":{"hits":[` + misplacedLocation + `]}}`
+	case strings.Contains(req.body, `"admin_level":6`):
+		return http.StatusOK, `{"hits":{"hits":[{"_id":"admin_60","_source":{"name":"Lalitpur","district":"Lalitpur","province":"Bagmati","province_ne":"बागमती"}}]}}`
+	case strings.HasSuffix(req.path, "/_search"):
+		return http.StatusOK, `{"hits":{"hits":[]}}`
+	}
+	return http.StatusOK, `{"result":"ok"}`
+}
+
+func TestValidateHierarchyPatchesProvinceNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		routing bool
+		// writePath identifies the request that writes the corrected document
+		writePath string
+		unwrap    func(body string) string
+	}{
+		{"routed document is moved", true, "/" + locationIndex + "/_doc/way_1", func(body string) string { return body }},
+		{"unrouted document is updated", false, "/" + locationIndex + "/_update/way_1", func(body string) string {
+			var update struct {
+				Doc json.RawMessage `json:"doc"`
+			}
+			json.Unmarshal([]byte(body), &update)
+			return string(update.Doc)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeES{respond: hierarchyES}
+			r := newFakeESResolver(t, fake)
+			r.RoutingOptimization = tt.routing
+
+			result, err := (&mutationResolver{r}).ValidateHierarchy(t.Context(), "way_1")
+			if err != nil {
+				t.Fatalf("ValidateHierarchy: %v", err)
+			}
+			if !result.Corrected {
+				t.Fatal("ValidateHierarchy made no correction")
+			}
+
+			var written map[string]interface{}
+			for _, req := range fake.requests {
+				if req.path == tt.writePath && (req.method == http.MethodPut || req.method == http.MethodPost) {
+					if err := json.Unmarshal([]byte(tt.unwrap(req.body)), &written); err != nil {
+						t.Fatalf("unmarshal written document: %v", err)
+					}
+				}
+			}
+			if written == nil {
+				t.Fatalf("no write to %s in %+v", tt.writePath, fake.requests)
+			}
+			if written["province"] != "Bagmati" {
+				t.Errorf("province = %v, want Bagmati", written["province"])
+			}
+			if written["province_number"] != float64(3) {
+				t.Errorf("province_number = %v, want 3", written["province_number"])
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"search-core/graph/model"
+)
+
+// historyIndex keeps previous versions of updated location documents
+const historyIndex = "nepal_locations_history"
+
+// locationSnapshot is a previous version of a location document
+type locationSnapshot struct {
+	LocationID       string    `json:"location_id"`
+	VersionTimestamp time.Time `json:"version_timestamp"`
+	Document         ESSource  `json:"document"`
+}
+
+// saveSnapshot writes the pre-update version of a location to the history index
+func (r *Resolver) saveSnapshot(ctx context.Context, id string, before ESSource) error {
+	var buf bytes.Buffer
+	snapshot := locationSnapshot{
+		LocationID:       id,
+		VersionTimestamp: time.Now().UTC(),
+		Document:         before,
+	}
+	if err := json.NewEncoder(&buf).Encode(snapshot); err != nil {
+		return fmt.Errorf("error encoding snapshot: %w", err)
+	}
+
+	res, err := r.ESClient.Index(historyIndex, &buf, r.ESClient.Index.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("error indexing snapshot: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+	return nil
+}
+
+// GetLocationHistory returns previous versions of a location, newest first
+func (r *queryResolver) GetLocationHistory(ctx context.Context, id string, limit *int) ([]*model.LocationSnapshot, error) {
+	query := map[string]interface{}{
+		"size": resolveLimit(limit),
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []map[string]interface{}{
+					{"term": map[string]interface{}{"location_id.keyword": id}},
+				},
+			},
+		},
+		"sort": []map[string]interface{}{
+			{"version_timestamp": map[string]interface{}{"order": "desc"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+		return nil, fmt.Errorf("error encoding query: %w", err)
+	}
+
+	// The history index only exists once a location has been updated
+	res, err := r.ESClient.Search(
+		r.ESClient.Search.WithContext(ctx),
+		r.ESClient.Search.WithIndex(historyIndex),
+		r.ESClient.Search.WithBody(&buf),
+		r.ESClient.Search.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error executing search: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+
+	var esResponse struct {
+		Hits struct {
+			Hits []struct {
+				Source locationSnapshot `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&esResponse); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	snapshots := make([]*model.LocationSnapshot, 0, len(esResponse.Hits.Hits))
+	for _, hit := range esResponse.Hits.Hits {
+		snapshots = append(snapshots, &model.LocationSnapshot{
+			VersionTimestamp: hit.Source.VersionTimestamp.Format(time.RFC3339),
+			Location:         convertToLocation(ESHit{ID: hit.Source.LocationID, Source: hit.Source.Document}),
+		})
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,65 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"search-core/graph/model"
+)
+
+// GetIndexStats reports the location index's size and shard layout from _cat/indices
+func (r *queryResolver) GetIndexStats(ctx context.Context) (*model.IndexStats, error) {
+	res, err := r.ESClient.Cat.Indices(
+		r.ESClient.Cat.Indices.WithContext(ctx),
+		r.ESClient.Cat.Indices.WithIndex(locationIndex),
+		r.ESClient.Cat.Indices.WithFormat("json"),
+		r.ESClient.Cat.Indices.WithBytes("b"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching index stats: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+
+	// _cat reports every value as a string
+	var rows []struct {
+		DocsCount string `json:"docs.count"`
+		StoreSize string `json:"store.size"`
+		Primaries string `json:"pri"`
+		Replicas  string `json:"rep"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("index %s not found", locationIndex)
+	}
+
+	stats := &model.IndexStats{}
+	for _, row := range rows {
+		stats.DocumentCount += atoiOrZero(row.DocsCount)
+		stats.IndexSizeBytes += int64(atoiOrZero(row.StoreSize))
+		stats.ShardCount += atoiOrZero(row.Primaries)
+		stats.ReplicaCount = atoiOrZero(row.Replicas)
+	}
+
+	meta, err := r.fetchIndexMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats.LastSyncAt = optionalStr(meta.LastSyncAt)
+	return stats, nil
+}
+
+// atoiOrZero parses a _cat value, treating missing values (closed indices) as zero
+func atoiOrZero(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
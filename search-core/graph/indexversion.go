@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// indexVersionTTL is how long the index version is cached before re-reading it
+const indexVersionTTL = 30 * time.Second
+
+// indexVersionCache holds the last _meta.version read from the location index
+type indexVersionCache struct {
+	mu        sync.Mutex
+	version   string
+	fetchedAt time.Time
+}
+
+// currentIndexVersion returns the location index's _meta.version, which the
+// syncer bumps after each sync. Lookup failures are logged and return the
+// last known version so searches never fail on them.
+func (r *Resolver) currentIndexVersion(ctx context.Context) *string {
+	c := &r.indexVersion
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.fetchedAt) >= indexVersionTTL {
+		meta, err := r.fetchIndexMeta(ctx)
+		if err != nil {
+			log.Printf("WARNING: failed to read index version: %v", err)
+		} else {
+			c.version = meta.Version
+			c.fetchedAt = time.Now()
+		}
+	}
+	return optionalStr(c.version)
+}
+
+// indexMeta is the _meta the syncer records on the location index after each sync
+type indexMeta struct {
+	Version    string `json:"version"`
+	LastSyncAt string `json:"last_sync_at"`
+}
+
+// fetchIndexMeta reads _meta from the location index mapping
+func (r *Resolver) fetchIndexMeta(ctx context.Context) (indexMeta, error) {
+	res, err := r.ESClient.Indices.GetMapping(
+		r.ESClient.Indices.GetMapping.WithContext(ctx),
+		r.ESClient.Indices.GetMapping.WithIndex(locationIndex),
+	)
+	if err != nil {
+		return indexMeta{}, fmt.Errorf("error fetching mapping: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return indexMeta{}, fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+
+	// Keyed by the concrete index name, which differs from locationIndex behind an alias
+	var mappings map[string]struct {
+		Mappings struct {
+			Meta indexMeta `json:"_meta"`
+		} `json:"mappings"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&mappings); err != nil {
+		return indexMeta{}, fmt.Errorf("error parsing response: %w", err)
+	}
+	for _, index := range mappings {
+		return index.Mappings.Meta, nil
+	}
+	return indexMeta{}, nil
+}
@@ -0,0 +1,59 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"search-core/graph/model"
+)
+
+// describeInterpretation explains in plain English what the search actually
+// ran with, e.g. "Interpreted as: text='Lalitpur', ward=5"
+func describeInterpretation(input model.LocationSearchInput) string {
+	parts := []string{fmt.Sprintf("text='%s'", strings.TrimSpace(input.Query))}
+	if isNepalPostalCode(input.Query) {
+		parts[0] = fmt.Sprintf("postalCode=%s", strings.TrimSpace(input.Query))
+	}
+
+	if input.SearchMode != nil && *input.SearchMode == model.SearchModePrefix {
+		parts = append(parts, "prefix match")
+	}
+	if canonical, ok := lookupSynonym(input.Query); ok {
+		parts = append(parts, fmt.Sprintf("alias of '%s'", canonical))
+	}
+	if input.Ward != nil {
+		parts = append(parts, fmt.Sprintf("ward=%d", *input.Ward))
+	}
+	if len(input.Wards) > 0 {
+		parts = append(parts, fmt.Sprintf("wards=%v", input.Wards))
+	}
+	if input.Municipality != nil && *input.Municipality != "" {
+		parts = append(parts, fmt.Sprintf("municipality='%s'", *input.Municipality))
+	}
+	if len(input.Municipalities) > 0 {
+		parts = append(parts, fmt.Sprintf("municipalities=%v", input.Municipalities))
+	}
+	if input.District != nil && *input.District != "" {
+		parts = append(parts, fmt.Sprintf("district='%s'", *input.District))
+	}
+	if input.Province != nil && *input.Province != "" {
+		parts = append(parts, fmt.Sprintf("province='%s'", *input.Province))
+	}
+	if input.ProvinceNumber != nil {
+		parts = append(parts, fmt.Sprintf("provinceNumber=%d", *input.ProvinceNumber))
+	}
+	if input.OsmID != nil {
+		parts = append(parts, fmt.Sprintf("osmId=%s", *input.OsmID))
+	}
+	if len(input.ExcludeIds) > 0 {
+		parts = append(parts, fmt.Sprintf("excludeIds=%v", input.ExcludeIds))
+	}
+	if input.EntityType != nil && *input.EntityType != "" {
+		parts = append(parts, fmt.Sprintf("entityType=%s", *input.EntityType))
+	}
+	if input.Country != nil && *input.Country != "" {
+		parts = append(parts, fmt.Sprintf("country=%s", *input.Country))
+	}
+
+	return "Interpreted as: " + strings.Join(parts, ", ")
+}
@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"context"
+
+	"search-core/graph/model"
+)
+
+// Admin levels of Nepal's administrative hierarchy
+const (
+	adminLevelProvince     = 4
+	adminLevelDistrict     = 6
+	adminLevelMunicipality = 7
+	adminLevelWard         = 9
+)
+
+// ListDistricts lists districts, optionally filtered by province
+func (r *queryResolver) ListDistricts(ctx context.Context, province *string, limit *int, after *string) (*model.LocationPage, error) {
+	return r.listAdminBoundaries(ctx, adminLevelDistrict, "province", province, "name.keyword", limit, after)
+}
+
+// ListMunicipalities lists municipalities, optionally filtered by district
+func (r *queryResolver) ListMunicipalities(ctx context.Context, district *string, municipalityType *model.MunicipalityType, limit *int, after *string) (*model.LocationPage, error) {
+	var extra []map[string]interface{}
+	if municipalityType != nil {
+		extra = append(extra, municipalityTypeClause(*municipalityType))
+	}
+	return r.listAdminBoundaries(ctx, adminLevelMunicipality, "district", district, "name.keyword", limit, after, extra...)
+}
+
+// ListWards lists the wards of a municipality
+func (r *queryResolver) ListWards(ctx context.Context, municipality string, limit *int, after *string) (*model.LocationPage, error) {
+	return r.listAdminBoundaries(ctx, adminLevelWard, "municipality", &municipality, "ward", limit, after)
+}
+
+// listAdminBoundaries pages through admin boundaries at one level, sorted by
+// sortField with an id tiebreaker so search_after cursors are stable. extra
+// filters are added to the level and parent filters.
+func (r *queryResolver) listAdminBoundaries(ctx context.Context, adminLevel int, parentField string, parent *string, sortField string, limit *int, after *string, extra ...map[string]interface{}) (*model.LocationPage, error) {
+	size := resolveLimit(limit)
+
+	filters := []map[string]interface{}{
+		{"term": map[string]interface{}{"entity_type": "admin_boundary"}},
+		{"term": map[string]interface{}{"admin_level": adminLevel}},
+	}
+	if parent != nil && *parent != "" {
+		filters = append(filters, exactNameClause(*parent, parentField+".keyword", parentField+"_ne.keyword"))
+	}
+	filters = append(filters, extra...)
+
+	query := map[string]interface{}{
+		"size": size,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{"filter": filters},
+		},
+		"sort": []map[string]interface{}{
+			{sortField: map[string]interface{}{"order": "asc"}},
+			{"id": map[string]interface{}{"order": "asc"}},
+		},
+	}
+	if after != nil {
+		searchAfter, err := decodeCursor(*after)
+		if err != nil {
+			return nil, err
+		}
+		query["search_after"] = searchAfter
+	}
+
+	esResponse, err := r.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LocationPage{
+		Items:      convertHits(esResponse.Hits.Hits),
+		Total:      esResponse.Hits.Total.Value,
+		NextCursor: nextCursor(esResponse.Hits.Hits, size),
+	}, nil
+}
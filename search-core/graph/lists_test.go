@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"search-core/graph/model"
+)
+
+// emptySearch answers every request with a search response without hits
+func emptySearch(esRequest) (int, string) {
+	return http.StatusOK, `{"hits":{"total":{"value":0},"hits":[]}}`
+}
+
+// sentFilters returns the bool filters of the last search sent to fake
+func sentFilters(t *testing.T, fake *fakeES) []interface{} {
+	t.Helper()
+	if len(fake.requests) == 0 {
+		t.Fatal("no search was sent")
+	}
+	var body struct {
+		Query struct {
+			Bool struct {
+				Filter []interface{} `json:"filter"`
+			} `json:"bool"`
+		} `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(fake.requests[len(fake.requests)-1].body), &body); err != nil {
+		t.Fatalf("unmarshal search body: %v", err)
+	}
+	return body.Query.Bool.Filter
+}
+
+// hasFilter reports whether filters contains want, compared as JSON
+func hasFilter(filters []interface{}, want map[string]interface{}) bool {
+	wantJSON, _ := json.Marshal(want)
+	for _, filter := range filters {
+		got, _ := json.Marshal(filter)
+		if string(got) == string(wantJSON) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestListAdminBoundariesExactParent(t *testing.T) {
+	fake := &fakeES{respond: emptySearch}
+	r := &queryResolver{newFakeESResolver(t, fake)}
+
+	if _, err := r.ListMunicipalities(t.Context(), strPtr(" Kathmandu "), nil, nil, nil); err != nil {
+		t.Fatalf("ListMunicipalities: %v", err)
+	}
+
+	want := exactNameClause("Kathmandu", "district.keyword", "district_ne.keyword")
+	if filters := sentFilters(t, fake); !hasFilter(filters, want) {
+		t.Errorf("filters = %v, want the exact district clause %v", filters, want)
+	}
+}
+
+func TestListMunicipalitiesType(t *testing.T) {
+	tests := []struct {
+		municipalityType model.MunicipalityType
+		want             string
+	}{
+		{model.MunicipalityTypeMetropolitan, "metropolitan"},
+		{model.MunicipalityTypeSubMetropolitan, "sub_metropolitan"},
+		{model.MunicipalityTypeMunicipality, "municipality"},
+		{model.MunicipalityTypeRuralMunicipality, "rural_municipality"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.municipalityType.String(), func(t *testing.T) {
+			fake := &fakeES{respond: emptySearch}
+			r := &queryResolver{newFakeESResolver(t, fake)}
+
+			if _, err := r.ListMunicipalities(t.Context(), nil, &tt.municipalityType, nil, nil); err != nil {
+				t.Fatalf("ListMunicipalities: %v", err)
+			}
+
+			want := map[string]interface{}{"term": map[string]interface{}{"municipality_type": tt.want}}
+			if filters := sentFilters(t, fake); !hasFilter(filters, want) {
+				t.Errorf("filters = %v, want %v", filters, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package graph
+
+import (
+	"context"
+	"strings"
+
+	"search-core/graph/model"
+)
+
+// GetLocationsByMunicipalityCode returns every location belonging to the
+// municipality with the given official local government code
+func (r *queryResolver) GetLocationsByMunicipalityCode(ctx context.Context, code string) ([]*model.Location, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, userError("code must not be empty")
+	}
+
+	query := map[string]interface{}{
+		"size": maxResultWindow,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []map[string]interface{}{
+					{"term": map[string]interface{}{"local_gov_code": code}},
+				},
+			},
+		},
+		"sort": []map[string]interface{}{
+			{"admin_level": map[string]interface{}{"order": "asc", "missing": "_last"}},
+			{"name.keyword": map[string]interface{}{"order": "asc"}},
+			{"id": map[string]interface{}{"order": "asc"}},
+		},
+	}
+
+	esResponse, err := r.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertHits(esResponse.Hits.Hits), nil
+}
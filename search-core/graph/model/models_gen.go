@@ -2,6 +2,92 @@
 
 package model
 
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Comparison of the top search results for two free-text addresses
+type AddressComparison struct {
+	// Similarity from 0.0 to 1.0: 1.0 when both resolve to the same location, otherwise
+	// weighted by shared province, district, municipality and ward, and proximity within 5 km
+	Score float64 `json:"score"`
+	// Both addresses are in the same province
+	SameProvince bool `json:"sameProvince"`
+	// Both addresses are in the same district
+	SameDistrict bool `json:"sameDistrict"`
+	// Both addresses are in the same municipality
+	SameMunicipality bool `json:"sameMunicipality"`
+	// Both addresses are in the same ward of the same municipality
+	SameWard bool `json:"sameWard"`
+	// Great-circle (Haversine) distance in meters, or null if either result has no coordinates
+	DistanceMeters *float64 `json:"distanceMeters,omitempty"`
+}
+
+// Free-form address to normalize
+type AddressInput struct {
+	// Address text, with components separated by commas (e.g. "Ward 5, Lalitpur, Bagmati")
+	Text string `json:"text"`
+}
+
+// A stored search whose new matches are sent to a webhook
+type Alert struct {
+	// Alert ID, sent as alert_id in webhook calls
+	ID string `json:"id"`
+	// The normalized search text
+	Query string `json:"query"`
+	// URL that receives matching locations
+	WebhookURL string `json:"webhookUrl"`
+	// Creation time (RFC 3339)
+	CreatedAt string `json:"createdAt"`
+}
+
+// A recorded mutation of a location
+type AuditLogEntry struct {
+	// When the mutation happened (RFC 3339)
+	Timestamp string `json:"timestamp"`
+	// Mutation name, e.g. validateHierarchy
+	Operation string `json:"operation"`
+	// ID of the mutated location
+	LocationID string `json:"locationId"`
+	// Identity that performed the mutation: X-Forwarded-User from a trusted proxy
+	// (TRUSTED_PROXY_CIDRS), else admin-key:<hash> for the admin API key, else anonymous
+	Actor string `json:"actor"`
+	// Location document before the mutation (serialized JSON)
+	BeforeState *string `json:"beforeState,omitempty"`
+	// Location document after the mutation (serialized JSON)
+	AfterState *string `json:"afterState,omitempty"`
+}
+
+// One page of the mutation audit log
+type AuditLogPage struct {
+	// Audit entries on this page
+	Items []*AuditLogEntry `json:"items"`
+	// Total number of matching audit entries
+	Total int `json:"total"`
+	// Cursor for the next page, or null when there are no more results
+	NextCursor *string `json:"nextCursor,omitempty"`
+}
+
+// A single aggregation bucket
+type FacetBucket struct {
+	// Bucket value
+	Key string `json:"key"`
+	// Number of documents in the bucket
+	Count int `json:"count"`
+}
+
+// One field's contribution to a search result's score
+type FieldScore struct {
+	// Matched index field (e.g. name, name_ne.fuzzy, search_text), or boost_score for the
+	// stored boost multiplier applied when results are re-ranked
+	Field string `json:"field"`
+	// Score contributed by the field before staleness decay and re-ranking, or the boost_score multiplier
+	Score float64 `json:"score"`
+}
+
 // Geographic point coordinates
 type GeoPoint struct {
 	// Latitude
@@ -10,6 +96,26 @@ type GeoPoint struct {
 	Lon float64 `json:"lon"`
 }
 
+// Geographic point coordinates input
+type GeoPointInput struct {
+	// Latitude
+	Lat float64 `json:"lat"`
+	// Longitude
+	Lon float64 `json:"lon"`
+}
+
+// Group of nearby search results sharing a geohash cell
+type GeohashCluster struct {
+	// Geohash cell key
+	Geohash string `json:"geohash"`
+	// Centroid of the locations in the cell
+	Centroid *GeoPoint `json:"centroid"`
+	// Number of matching locations in the cell
+	Count int `json:"count"`
+	// Name of the top-ranked location in the cell
+	TopLocationName string `json:"topLocationName"`
+}
+
 // Health status of the service
 type HealthStatus struct {
 	// Service status
@@ -20,11 +126,25 @@ type HealthStatus struct {
 	Version string `json:"version"`
 }
 
+// Health overview of the location index
+type IndexStats struct {
+	// Documents in the index (primaries only)
+	DocumentCount int `json:"documentCount"`
+	// Total store size including replicas, in bytes
+	IndexSizeBytes int64 `json:"indexSizeBytes"`
+	// When the last sync completed (RFC 3339), if recorded
+	LastSyncAt *string `json:"lastSyncAt,omitempty"`
+	// Number of primary shards
+	ShardCount int `json:"shardCount"`
+	// Number of replicas per primary shard
+	ReplicaCount int `json:"replicaCount"`
+}
+
 // Location entity with complete administrative hierarchy
 type Location struct {
 	// Unique identifier
 	ID string `json:"id"`
-	// Entity type: place, admin_boundary, poi, road
+	// Entity type: place, admin_boundary, poi, amenity, road, highway
 	EntityType string `json:"entityType"`
 	// Primary name (may be in Nepali or English)
 	Name string `json:"name"`
@@ -44,6 +164,9 @@ type Location struct {
 	Municipality *string `json:"municipality,omitempty"`
 	// Municipality name in Nepali
 	MunicipalityNe *string `json:"municipalityNe,omitempty"`
+	// Local government type of a municipality: metropolitan, sub_metropolitan, municipality
+	// or rural_municipality (null for other locations or when unknown)
+	MunicipalityType *string `json:"municipalityType,omitempty"`
 	// District name
 	District *string `json:"district,omitempty"`
 	// District name in Nepali
@@ -52,10 +175,76 @@ type Location struct {
 	Province *string `json:"province,omitempty"`
 	// Province name in Nepali
 	ProvinceNe *string `json:"provinceNe,omitempty"`
+	// Province number (1=Koshi, 2=Madhesh, 3=Bagmati, 4=Gandaki, 5=Lumbini, 6=Karnali, 7=Sudurpashchim)
+	ProvinceNumber *int `json:"provinceNumber,omitempty"`
 	// Country (always "Nepal")
 	Country string `json:"country"`
+	// When the location was last synced from OSM (ISO-8601)
+	LastUpdated *string `json:"lastUpdated,omitempty"`
+	// OSM element ID (a string, as it can exceed JSON number precision); link with
+	// https://www.openstreetmap.org/{node|way|relation}/{osmId}
+	OsmID *string `json:"osmId,omitempty"`
+	// OSM tags of amenity and tourism locations, e.g. {"amenity": "hospital", "beds": "150"}
+	MatchedTags map[string]any `json:"matchedTags,omitempty"`
 	// Search relevance score
 	Score float64 `json:"score"`
+	// Cursor positioned after this location in search results; pass as after to continue from here
+	Cursor *string `json:"cursor,omitempty"`
+	// Match confidence between 0.0 and 1.0
+	// Computed as the hit's score divided by the maximum possible score of the query, capped at 1,
+	// multiplied by the Levenshtein similarity between the query and the closest of the location's
+	// names. The maximum is the score of a known-perfect document: the top hit's ES _explain output
+	// recomputed as if each field it matched held exactly the query (every term once, at its exact
+	// rather than fuzzy match boost). Only computed when selected, since it costs an _explain request.
+	// Null when the explanation cannot be fetched.
+	MatchConfidence *float64 `json:"matchConfidence,omitempty"`
+	// Elasticsearch score explanation as serialized JSON (only set in explain mode)
+	ScoreExplanation *string `json:"scoreExplanation,omitempty"`
+	// Score contribution of each matched field, highest first (only set with showFieldScores)
+	FieldScores []*FieldScore `json:"fieldScores,omitempty"`
+}
+
+// Number of indexed locations in one admin division
+type LocationAggregation struct {
+	// Division name
+	Name string `json:"name"`
+	// Division name in Nepali
+	NameNe *string `json:"nameNe,omitempty"`
+	// Number of indexed locations in the division
+	Count int `json:"count"`
+	// Centroid of the division's locations (null when none have coordinates)
+	Centroid *GeoPoint `json:"centroid,omitempty"`
+}
+
+// Comparison of two locations
+type LocationComparison struct {
+	// Both locations are in the same province
+	SameProvince bool `json:"sameProvince"`
+	// Both locations are in the same district
+	SameDistrict bool `json:"sameDistrict"`
+	// Both locations are in the same municipality
+	SameMunicipality bool `json:"sameMunicipality"`
+	// Both locations are in the same ward of the same municipality
+	SameWard bool `json:"sameWard"`
+	// Great-circle (Haversine) distance in meters, or null if either location has no coordinates
+	DistanceBetweenMeters *float64 `json:"distanceBetweenMeters,omitempty"`
+}
+
+// A search result and its pagination cursor
+type LocationEdge struct {
+	Node *Location `json:"node"`
+	// Cursor positioned after this result (empty when the result has no cursor, e.g. fallback results)
+	Cursor string `json:"cursor"`
+}
+
+// One page of a location list
+type LocationPage struct {
+	// Locations on this page
+	Items []*Location `json:"items"`
+	// Total number of locations in the list
+	Total int `json:"total"`
+	// Cursor for the next page, or null when there are no more results
+	NextCursor *string `json:"nextCursor,omitempty"`
 }
 
 // Input for location search with optional parent validation
@@ -64,14 +253,87 @@ type LocationSearchInput struct {
 	Query string `json:"query"`
 	// Optional: Expected ward number for validation
 	Ward *int `json:"ward,omitempty"`
+	// Optional: Match any of these ward numbers (e.g. a delivery zone). Cannot be combined with ward.
+	Wards []int `json:"wards,omitempty"`
 	// Optional: Expected municipality name for validation
 	Municipality *string `json:"municipality,omitempty"`
+	// Optional: Match any of these municipalities (e.g. delivery zones). Merged with municipality when both are set.
+	Municipalities []string `json:"municipalities,omitempty"`
 	// Optional: Expected district name for validation
 	District *string `json:"district,omitempty"`
 	// Optional: Expected province name for validation
 	Province *string `json:"province,omitempty"`
+	// Optional: Province number (1-7) to filter by
+	ProvinceNumber *int `json:"provinceNumber,omitempty"`
+	// Optional: OSM element ID to filter by
+	OsmID *string `json:"osmId,omitempty"`
+	// Optional: Location IDs to leave out of the results, e.g. one the user already picked (at most 100)
+	ExcludeIds []string `json:"excludeIds,omitempty"`
+	// ISO 3166-1 alpha-2 country code to search in (default: ES_DEFAULT_COUNTRY, normally "NP")
+	Country *string `json:"country,omitempty"`
 	// Maximum number of results to return (default: 10, max: 50)
 	Limit *int `json:"limit,omitempty"`
+	// Number of results to skip (offset + limit may not exceed 10,000). Simple to use, but Elasticsearch must
+	// collect and discard every skipped hit, so deep pages get progressively slower.
+	// Cannot be combined with after.
+	Offset *int `json:"offset,omitempty"`
+	// Cursor from a previous response's nextCursor. Uses search_after, which costs the same
+	// for every page and is preferred for deep pagination. Cannot be combined with offset.
+	After *string `json:"after,omitempty"`
+	// Include the Elasticsearch score explanation on each result (requires ENABLE_DEBUG_FEATURES=true)
+	Explain *bool `json:"explain,omitempty"`
+	// Explain only the top result, and only when its score is over 10x the second result's
+	// (requires ENABLE_DEBUG_FEATURES=true; ignored when explain is set)
+	ExplainTop *bool `json:"explainTop,omitempty"`
+	// Include each result's score broken down by matched field in fieldScores
+	// (requires ENABLE_DEBUG_FEATURES=true)
+	ShowFieldScores *bool `json:"showFieldScores,omitempty"`
+	// Return Elasticsearch query profiling data in queryProfile (requires ENABLE_DEBUG_FEATURES=true)
+	ProfileQuery *bool `json:"profileQuery,omitempty"`
+	// Result ordering (default: RELEVANCE)
+	SortBy *LocationSortMode `json:"sortBy,omitempty"`
+	// How the query text is matched (default: FUZZY)
+	SearchMode *SearchMode `json:"searchMode,omitempty"`
+	// Ranking variant for A/B tests (default: CONTROL). Each variant's boosts, fuzziness
+	// and sort order come from SEARCH_VARIANTS_FILE; the variant is logged with the searchId.
+	SearchVariant *SearchVariant `json:"searchVariant,omitempty"`
+	// Reference point for DISTANCE sorting
+	NearPoint *GeoPointInput `json:"nearPoint,omitempty"`
+	// Location fields to return (e.g. ["name", "nameEn", "district"]); others are left null.
+	// id and score are always returned. Omit to return all fields.
+	Fields []string `json:"fields,omitempty"`
+	// When the search finds nothing, retry with progressively fewer filters (ward, then municipality,
+	// district, province) until results are found. Dropped filters are listed in relaxedFilters.
+	EnableFallbackSearch *bool `json:"enableFallbackSearch,omitempty"`
+	// Geohash precision (1-12) for map clustering. When set, results is empty and matches
+	// are grouped into clusters instead.
+	ClusterByGeohash *int `json:"clusterByGeohash,omitempty"`
+	// Optional: Only match this entity type (e.g. place, admin_boundary, amenity)
+	EntityType *string `json:"entityType,omitempty"`
+	// Return up to limit random documents for data quality spot checks. The query text is
+	// ignored; entityType still applies.
+	Sample *bool `json:"sample,omitempty"`
+	// Optional: Include soft-deleted locations in the results (admin only)
+	IncludeDeleted *bool `json:"includeDeleted,omitempty"`
+}
+
+// Normalized search input echoed back in a search response
+type LocationSearchInputEcho struct {
+	Query          string           `json:"query"`
+	Ward           *int             `json:"ward,omitempty"`
+	Wards          []int            `json:"wards,omitempty"`
+	Municipality   *string          `json:"municipality,omitempty"`
+	Municipalities []string         `json:"municipalities,omitempty"`
+	District       *string          `json:"district,omitempty"`
+	Province       *string          `json:"province,omitempty"`
+	ProvinceNumber *int             `json:"provinceNumber,omitempty"`
+	OsmID          *string          `json:"osmId,omitempty"`
+	EntityType     *string          `json:"entityType,omitempty"`
+	Country        *string          `json:"country,omitempty"`
+	Limit          int              `json:"limit"`
+	Offset         *int             `json:"offset,omitempty"`
+	SortBy         LocationSortMode `json:"sortBy"`
+	SearchMode     SearchMode       `json:"searchMode"`
 }
 
 // Response containing search results
@@ -82,13 +344,133 @@ type LocationSearchResponse struct {
 	Total int `json:"total"`
 	// Query execution time in milliseconds
 	Took int `json:"took"`
+	// Elasticsearch query profile (serialized JSON) when profileQuery was requested
+	QueryProfile *string `json:"queryProfile,omitempty"`
+	// Results 2-4 when the second result scores within AMBIGUITY_SCORE_GAP_PERCENT (default 15%)
+	// of the top result, for prompting "Did you mean one of these?" (null for clear matches)
+	Alternatives []*Location `json:"alternatives,omitempty"`
+	// Up to 10 municipalities sharing a border with the top result, in name order
+	// (null unless the top result is a municipality)
+	Neighbors []*Location `json:"neighbors,omitempty"`
+	// The search input after normalization (trimmed and sanitized query, ward pulled out of
+	// the query text, defaults filled in), as actually used for the search
+	ParsedFilters *LocationSearchInputEcho `json:"parsedFilters,omitempty"`
+	// True when the search index was unreachable and results come from a small static
+	// gazetteer of provinces and major cities instead
+	IsFallback *bool `json:"isFallback,omitempty"`
+	// Version of the indexed data, changed by every sync. Clients can drop locally cached
+	// results when it changes. Null if the index has no version yet.
+	IndexVersion *string `json:"indexVersion,omitempty"`
+	// Unique ID of this search, also sent in the X-Search-ID header. Quote it in bug reports.
+	SearchID *string `json:"searchId,omitempty"`
+	// Score explanation (serialized JSON) for a dominant top result when explainTop was requested
+	TopResultExplanation *string `json:"topResultExplanation,omitempty"`
+	// Highest result score, for normalizing scores to 0-1 (null when not sorted by relevance)
+	MaxScore *float64 `json:"maxScore,omitempty"`
+	// Cursor for the next page, or null when there are no more results
+	NextCursor *string `json:"nextCursor,omitempty"`
+	// Plain-English description of how the query was interpreted (e.g. Interpreted as: text='Lalitpur', ward=5)
+	QueryInterpretation *string `json:"queryInterpretation,omitempty"`
+	// Map clusters when clusterByGeohash is set (empty otherwise)
+	Clusters []*GeohashCluster `json:"clusters"`
+	// Filters dropped by fallback search to find these results (empty when none were dropped)
+	RelaxedFilters []string `json:"relaxedFilters,omitempty"`
 	// Validation result if parent filters were provided
 	Validation *ValidationResult `json:"validation,omitempty"`
 }
 
+// A previous version of a location
+type LocationSnapshot struct {
+	// When this version was replaced (RFC 3339)
+	VersionTimestamp string `json:"versionTimestamp"`
+	// The location as it was before the update
+	Location *Location `json:"location"`
+}
+
+// Aggregate statistics for a municipality
+type MunicipalityStats struct {
+	// Highest ward number found in the municipality
+	TotalWards int `json:"totalWards"`
+	// Number of indexed locations in the municipality
+	TotalLocations int `json:"totalLocations"`
+	// Location counts grouped by entity type
+	LocationsByType []*FacetBucket `json:"locationsByType"`
+	// Geographic centroid of all locations in the municipality
+	Centroid *GeoPoint `json:"centroid,omitempty"`
+}
+
+type Mutation struct {
+}
+
+// Structured components of a normalized address
+type NormalizedAddress struct {
+	// Ward number
+	Ward *int `json:"ward,omitempty"`
+	// Municipality name
+	Municipality *string `json:"municipality,omitempty"`
+	// District name
+	District *string `json:"district,omitempty"`
+	// Province name
+	Province *string `json:"province,omitempty"`
+	// Confidence from 0.0 to 1.0, reduced for each unmatched, corrected or conflicting component
+	Confidence float64 `json:"confidence"`
+	// Problems found while normalizing, e.g. corrected misspellings
+	Warnings []string `json:"warnings"`
+}
+
+// Relay pagination state
+type PageInfo struct {
+	HasNextPage     bool `json:"hasNextPage"`
+	HasPreviousPage bool `json:"hasPreviousPage"`
+	// Cursor of the first edge
+	StartCursor *string `json:"startCursor,omitempty"`
+	// Cursor to pass as after for the next page
+	EndCursor *string `json:"endCursor,omitempty"`
+}
+
 type Query struct {
 }
 
+// A partial reindex job for one province
+type ReindexStatus struct {
+	JobID string `json:"jobId"`
+	// Province being reindexed (lowercased English name)
+	Province string `json:"province"`
+	// pending, running, completed or failed
+	Status string `json:"status"`
+	// Documents indexed in the province's bounding box when the job was queued
+	EstimatedDocuments int `json:"estimatedDocuments"`
+	// Documents reindexed, once the job has completed
+	IndexedDocuments *int `json:"indexedDocuments,omitempty"`
+	// Failure reason when status is failed
+	Error *string `json:"error,omitempty"`
+	// When the job was queued (RFC 3339)
+	RequestedAt string `json:"requestedAt"`
+	// When the job completed or failed (RFC 3339)
+	CompletedAt *string `json:"completedAt,omitempty"`
+}
+
+// Relay connection of search results
+type SearchLocationConnection struct {
+	Edges    []*LocationEdge `json:"edges"`
+	PageInfo *PageInfo       `json:"pageInfo"`
+	// Total number of matching locations
+	TotalCount int `json:"totalCount"`
+}
+
+type Subscription struct {
+}
+
+// Result of checking and correcting a location's parent hierarchy
+type ValidationCorrectionResult struct {
+	// Location identifier
+	ID string `json:"id"`
+	// Whether the document was patched
+	Corrected bool `json:"corrected"`
+	// Human-readable description of each corrected field
+	Changes []string `json:"changes"`
+}
+
 // Details about a validation mismatch
 type ValidationMismatch struct {
 	// Field name that mismatched
@@ -108,3 +490,248 @@ type ValidationResult struct {
 	// Validation message
 	Message *string `json:"message,omitempty"`
 }
+
+// Result ordering for location search
+type LocationSortMode string
+
+const (
+	// Best match first, then by boost score
+	LocationSortModeRelevance LocationSortMode = "RELEVANCE"
+	// Alphabetical by name
+	LocationSortModeNameAsc LocationSortMode = "NAME_ASC"
+	// Reverse alphabetical by name
+	LocationSortModeNameDesc LocationSortMode = "NAME_DESC"
+	// Closest to nearPoint first (requires nearPoint)
+	LocationSortModeDistance LocationSortMode = "DISTANCE"
+	// Broadest administrative level first (province before district, etc.)
+	LocationSortModeAdminLevelAsc LocationSortMode = "ADMIN_LEVEL_ASC"
+)
+
+var AllLocationSortMode = []LocationSortMode{
+	LocationSortModeRelevance,
+	LocationSortModeNameAsc,
+	LocationSortModeNameDesc,
+	LocationSortModeDistance,
+	LocationSortModeAdminLevelAsc,
+}
+
+func (e LocationSortMode) IsValid() bool {
+	switch e {
+	case LocationSortModeRelevance, LocationSortModeNameAsc, LocationSortModeNameDesc, LocationSortModeDistance, LocationSortModeAdminLevelAsc:
+		return true
+	}
+	return false
+}
+
+func (e LocationSortMode) String() string {
+	return string(e)
+}
+
+func (e *LocationSortMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LocationSortMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LocationSortMode", str)
+	}
+	return nil
+}
+
+func (e LocationSortMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *LocationSortMode) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e LocationSortMode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Local government type of a municipality
+type MunicipalityType string
+
+const (
+	// Metropolitan city (mahanagarpalika)
+	MunicipalityTypeMetropolitan MunicipalityType = "METROPOLITAN"
+	// Sub-metropolitan city (upamahanagarpalika)
+	MunicipalityTypeSubMetropolitan MunicipalityType = "SUB_METROPOLITAN"
+	// Municipality (nagarpalika)
+	MunicipalityTypeMunicipality MunicipalityType = "MUNICIPALITY"
+	// Rural municipality (gaunpalika)
+	MunicipalityTypeRuralMunicipality MunicipalityType = "RURAL_MUNICIPALITY"
+)
+
+var AllMunicipalityType = []MunicipalityType{
+	MunicipalityTypeMetropolitan,
+	MunicipalityTypeSubMetropolitan,
+	MunicipalityTypeMunicipality,
+	MunicipalityTypeRuralMunicipality,
+}
+
+func (e MunicipalityType) IsValid() bool {
+	switch e {
+	case MunicipalityTypeMetropolitan, MunicipalityTypeSubMetropolitan, MunicipalityTypeMunicipality, MunicipalityTypeRuralMunicipality:
+		return true
+	}
+	return false
+}
+
+func (e MunicipalityType) String() string {
+	return string(e)
+}
+
+func (e *MunicipalityType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MunicipalityType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MunicipalityType", str)
+	}
+	return nil
+}
+
+func (e MunicipalityType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *MunicipalityType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e MunicipalityType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Text matching strategy for location search
+type SearchMode string
+
+const (
+	// Fuzzy matching that tolerates misspellings
+	SearchModeFuzzy SearchMode = "FUZZY"
+	// Exact prefix matching for autocomplete (e.g. Kath finds Kathmandu)
+	SearchModePrefix SearchMode = "PREFIX"
+)
+
+var AllSearchMode = []SearchMode{
+	SearchModeFuzzy,
+	SearchModePrefix,
+}
+
+func (e SearchMode) IsValid() bool {
+	switch e {
+	case SearchModeFuzzy, SearchModePrefix:
+		return true
+	}
+	return false
+}
+
+func (e SearchMode) String() string {
+	return string(e)
+}
+
+func (e *SearchMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SearchMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SearchMode", str)
+	}
+	return nil
+}
+
+func (e SearchMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SearchMode) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SearchMode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// Ranking variant of an A/B test
+type SearchVariant string
+
+const (
+	SearchVariantControl   SearchVariant = "CONTROL"
+	SearchVariantTreatment SearchVariant = "TREATMENT"
+)
+
+var AllSearchVariant = []SearchVariant{
+	SearchVariantControl,
+	SearchVariantTreatment,
+}
+
+func (e SearchVariant) IsValid() bool {
+	switch e {
+	case SearchVariantControl, SearchVariantTreatment:
+		return true
+	}
+	return false
+}
+
+func (e SearchVariant) String() string {
+	return string(e)
+}
+
+func (e *SearchVariant) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SearchVariant(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SearchVariant", str)
+	}
+	return nil
+}
+
+func (e SearchVariant) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SearchVariant) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SearchVariant) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
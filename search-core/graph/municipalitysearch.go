@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"search-core/graph/model"
+	"search-core/internal/normalize"
+)
+
+// municipalitySearchFields are the source fields a municipality result needs;
+// tags, boundaries and search helper fields are never fetched
+var municipalitySearchFields = []string{
+	"entity_type", "name", "name_ne", "name_en", "admin_level", "location",
+	"municipality", "municipality_ne", "municipality_type",
+	"district", "district_ne", "province", "province_ne", "province_number",
+	"country", "osm_id", "last_updated",
+}
+
+// MunicipalitySearch searches municipalities by name, optionally of one
+// local government type. Unlike searchLocation it has no parent filters, so
+// results are not validated.
+func (r *queryResolver) MunicipalitySearch(ctx context.Context, query string, typeArg *model.MunicipalityType, limit *int) ([]*model.Location, error) {
+	query = normalize.DevanagariNumerals(normalize.SanitizeQuery(query))
+	if utf8.RuneCountInString(query) < r.MinQueryLength {
+		return nil, userError("Query must be at least %d characters", r.MinQueryLength)
+	}
+
+	filter := []map[string]interface{}{
+		{"term": map[string]interface{}{"entity_type": "admin_boundary"}},
+		{"term": map[string]interface{}{"admin_level": adminLevelMunicipality}},
+	}
+	if typeArg != nil {
+		filter = append(filter, municipalityTypeClause(*typeArg))
+	}
+
+	esQuery := map[string]interface{}{
+		"size":    resolveLimit(limit),
+		"_source": map[string]interface{}{"includes": municipalitySearchFields},
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must":     []map[string]interface{}{r.textMatchClause(query, 1, nil)},
+				"filter":   filter,
+				"must_not": []map[string]interface{}{notDeletedClause},
+			},
+		},
+	}
+
+	esResponse, err := r.search(ctx, esQuery)
+	if err != nil {
+		return nil, err
+	}
+	return convertHits(esResponse.Hits.Hits), nil
+}
+
+// municipalityTypeClause filters municipalities by local government type,
+// stored by the syncer as the lowercased enum value (e.g. sub_metropolitan)
+func municipalityTypeClause(t model.MunicipalityType) map[string]interface{} {
+	return map[string]interface{}{
+		"term": map[string]interface{}{"municipality_type": strings.ToLower(t.String())},
+	}
+}
@@ -0,0 +1,38 @@
+package graph
+
+import (
+	"context"
+
+	"search-core/graph/model"
+)
+
+// maxNearestNeighbors caps k for NearestNeighbors
+const maxNearestNeighbors = 100
+
+// NearestNeighbors returns the k locations closest to a point using an
+// approximate kNN search on location_vector ([lon, lat]). Distances are
+// Euclidean in degrees, which ranks well at Nepal's scale but is not exact.
+func (r *queryResolver) NearestNeighbors(ctx context.Context, lat float64, lon float64, k int) ([]*model.Location, error) {
+	if k < 1 || k > maxNearestNeighbors {
+		return nil, userError("k must be between 1 and %d", maxNearestNeighbors)
+	}
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return nil, userError("lat must be between -90 and 90 and lon between -180 and 180")
+	}
+
+	query := map[string]interface{}{
+		"size": k,
+		"knn": map[string]interface{}{
+			"field":          "location_vector",
+			"query_vector":   []float64{lon, lat},
+			"k":              k,
+			"num_candidates": max(k*10, 100),
+		},
+	}
+
+	esResponse, err := r.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return convertHits(esResponse.Hits.Hits), nil
+}
@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"context"
+	"log"
+
+	"search-core/graph/model"
+)
+
+// maxNeighbors caps the adjacent municipalities returned with a search
+const maxNeighbors = 10
+
+// neighboringMunicipalities returns the municipalities whose boundary
+// polygons intersect the top result's, or nil when the top result is not a
+// municipality. Failures are logged, never returned, since neighbors only
+// enrich the response.
+func (r *Resolver) neighboringMunicipalities(ctx context.Context, hits []ESHit, results []*model.Location) []*model.Location {
+	if len(results) == 0 {
+		return nil
+	}
+
+	var top *ESHit
+	for i := range hits {
+		if hits[i].ID == results[0].ID {
+			top = &hits[i]
+			break
+		}
+	}
+	if top == nil || top.Source.EntityType != "admin_boundary" || top.Source.AdminLevel != adminLevelMunicipality {
+		return nil
+	}
+
+	// The polygon is read from the indexed document rather than sent back to ES
+	shape := map[string]interface{}{
+		"index": locationIndex,
+		"id":    top.ID,
+		"path":  "boundary",
+	}
+	if top.Routing != "" {
+		shape["routing"] = top.Routing
+	}
+
+	query := map[string]interface{}{
+		"size": maxNeighbors,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []map[string]interface{}{
+					{"term": map[string]interface{}{"entity_type": "admin_boundary"}},
+					{"term": map[string]interface{}{"admin_level": adminLevelMunicipality}},
+					{"geo_shape": map[string]interface{}{
+						"boundary": map[string]interface{}{
+							"indexed_shape": shape,
+							"relation":      "intersects",
+						},
+					}},
+				},
+				"must_not": []map[string]interface{}{
+					{"ids": map[string]interface{}{"values": []string{top.ID}}},
+					notDeletedClause,
+				},
+			},
+		},
+		"sort": []map[string]interface{}{
+			{"name.keyword": map[string]interface{}{"order": "asc"}},
+		},
+	}
+
+	esResponse, err := r.search(ctx, query)
+	if err != nil {
+		log.Printf("WARNING: failed to find neighbors of %s: %v", top.ID, err)
+		return nil
+	}
+	return convertHits(esResponse.Hits.Hits)
+}
@@ -0,0 +1,88 @@
+package graph
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestNeighboringMunicipalities(t *testing.T) {
+	fake := &fakeES{respond: func(req esRequest) (int, string) {
+		return http.StatusOK, `{"hits":{"total":{"value":2},"hits":[
+			{"_id":"admin_12","_source":{"entity_type":"admin_boundary","name":"Kirtipur","admin_level":7}},
+			{"_id":"admin_13","_source":{"entity_type":"admin_boundary","name":"Lalitpur","admin_level":7}}]}}`
+	}}
+	r := newFakeESResolver(t, fake)
+
+	hits := []ESHit{{
+		ID:      "admin_11",
+		Routing: "bagmati",
+		Source:  ESSource{EntityType: "admin_boundary", Name: "Kathmandu", AdminLevel: adminLevelMunicipality},
+	}}
+	neighbors := r.neighboringMunicipalities(t.Context(), hits, convertHits(hits))
+
+	if len(fake.requests) != 1 {
+		t.Fatalf("got %d requests, want 1: %+v", len(fake.requests), fake.requests)
+	}
+	if got := fake.requests[0].path; got != "/"+locationIndex+"/_search" {
+		t.Errorf("path = %s, want a search of %s", got, locationIndex)
+	}
+
+	var sent struct {
+		Query struct {
+			Bool struct {
+				Filter []map[string]json.RawMessage `json:"filter"`
+			} `json:"bool"`
+		} `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(fake.requests[0].body), &sent); err != nil {
+		t.Fatalf("unmarshal query: %v", err)
+	}
+	var shape *struct {
+		Boundary struct {
+			IndexedShape map[string]string `json:"indexed_shape"`
+			Relation     string            `json:"relation"`
+		} `json:"boundary"`
+	}
+	for _, filter := range sent.Query.Bool.Filter {
+		if raw, ok := filter["geo_shape"]; ok {
+			if err := json.Unmarshal(raw, &shape); err != nil {
+				t.Fatalf("unmarshal geo_shape: %v", err)
+			}
+		}
+	}
+	if shape == nil {
+		t.Fatalf("no geo_shape filter in %s", fake.requests[0].body)
+	}
+	if shape.Boundary.Relation != "intersects" {
+		t.Errorf("relation = %q, want intersects", shape.Boundary.Relation)
+	}
+	wantShape := map[string]string{"index": locationIndex, "id": "admin_11", "path": "boundary", "routing": "bagmati"}
+	for key, want := range wantShape {
+		if got := shape.Boundary.IndexedShape[key]; got != want {
+			t.Errorf("indexed_shape.%s = %q, want %q", key, got, want)
+		}
+	}
+
+	if len(neighbors) != 2 || neighbors[0].Name != "Kirtipur" || neighbors[1].Name != "Lalitpur" {
+		t.Fatalf("neighbors = %+v, want Kirtipur and Lalitpur", neighbors)
+	}
+	if neighbors[0].ID != "admin_12" {
+		t.Errorf("first neighbor ID = %s, want admin_12", neighbors[0].ID)
+	}
+}
+
+func TestNeighboringMunicipalitiesSkipsOtherEntities(t *testing.T) {
+	fake := &fakeES{respond: func(req esRequest) (int, string) {
+		return http.StatusOK, `{"hits":{"hits":[]}}`
+	}}
+	r := newFakeESResolver(t, fake)
+
+	hits := []ESHit{{ID: "admin_5", Source: ESSource{EntityType: "admin_boundary", Name: "Kathmandu", AdminLevel: 6}}}
+	if neighbors := r.neighboringMunicipalities(t.Context(), hits, convertHits(hits)); neighbors != nil {
+		t.Errorf("neighbors = %+v, want nil for a district", neighbors)
+	}
+	if len(fake.requests) != 0 {
+		t.Errorf("got %d requests, want none for a district", len(fake.requests))
+	}
+}
@@ -0,0 +1,182 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"search-core/graph/model"
+	"search-core/internal/queryparser"
+)
+
+// addressComponent is an admin division an address can name
+type addressComponent struct {
+	field      string
+	adminLevel int
+}
+
+var (
+	municipalityComponent = addressComponent{field: "municipality", adminLevel: 7}
+	districtComponent     = addressComponent{field: "district", adminLevel: 6}
+	provinceComponent     = addressComponent{field: "province", adminLevel: 4}
+)
+
+// Components without a division keyword are tried finest first, the order
+// addresses are usually written in
+var addressComponents = []addressComponent{municipalityComponent, districtComponent, provinceComponent}
+
+var componentsByRole = map[queryparser.AddressRole]addressComponent{
+	queryparser.RoleMunicipality: municipalityComponent,
+	queryparser.RoleDistrict:     districtComponent,
+	queryparser.RoleProvince:     provinceComponent,
+}
+
+// Confidence lost for each kind of problem found while normalizing
+const (
+	unmatchedPenalty = 0.25
+	conflictPenalty  = 0.2
+	correctedPenalty = 0.1
+)
+
+// NormalizeAddress parses a free-form address into ward, municipality, district
+// and province, validating each named division against the indexed admin boundaries
+func (r *mutationResolver) NormalizeAddress(ctx context.Context, input model.AddressInput) (*model.NormalizedAddress, error) {
+	parsed := queryparser.ParseAddress(input.Text)
+
+	result := &model.NormalizedAddress{Ward: parsed.Ward, Warnings: []string{}}
+	confidence := 1.0
+	warn := func(penalty float64, format string, args ...interface{}) {
+		confidence -= penalty
+		result.Warnings = append(result.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	matched := map[string]*ESSource{}
+	for _, part := range parsed.Parts {
+		if canonical, ok := lookupSynonym(part.Raw); ok {
+			part.Text = canonical
+		}
+
+		component, hit, err := r.resolveAddressPart(ctx, part, matched)
+		if err != nil {
+			return nil, err
+		}
+		if hit == nil {
+			warn(unmatchedPenalty, "Could not match %q to a known municipality, district or province", part.Raw)
+			continue
+		}
+
+		name, nameNe := adminField(&hit.Source, component.field)
+		if !namesAgree(name, part.Text) && !namesAgree(nameNe, part.Text) {
+			warn(correctedPenalty, "Corrected %s %q to %q", component.field, part.Text, name)
+		}
+		matched[component.field] = &hit.Source
+	}
+
+	// The finest matched division determines its parents; coarser components
+	// that contradict it are reported and overridden
+	values := map[string]string{}
+	var finest *ESSource
+	var finestName string
+	for _, component := range addressComponents {
+		src := matched[component.field]
+		if src == nil {
+			continue
+		}
+		value, _ := adminField(src, component.field)
+		if finest == nil {
+			finest, finestName = src, value
+		} else if expected, _ := adminField(finest, component.field); expected != "" {
+			if !stringsMatch(expected, value) {
+				warn(conflictPenalty, "%s is in %s, not %s", finestName, expected, value)
+			}
+			value = expected
+		}
+		values[component.field] = value
+	}
+	if finest != nil {
+		for _, component := range addressComponents {
+			if values[component.field] == "" {
+				values[component.field], _ = adminField(finest, component.field)
+			}
+		}
+	}
+	result.Municipality = optionalStr(values[municipalityComponent.field])
+	result.District = optionalStr(values[districtComponent.field])
+	result.Province = optionalStr(values[provinceComponent.field])
+
+	if result.Ward != nil && result.Municipality == nil {
+		warn(correctedPenalty, "Ward %d given without a municipality", *result.Ward)
+	}
+	if len(matched) == 0 {
+		confidence = 0
+	}
+	result.Confidence = math.Max(0, confidence)
+
+	return result, nil
+}
+
+// resolveAddressPart matches an address component to an admin boundary. A
+// component with a division keyword is only matched at that division; others
+// are tried at each division not already matched.
+func (r *Resolver) resolveAddressPart(ctx context.Context, part queryparser.AddressPart, matched map[string]*ESSource) (addressComponent, *ESHit, error) {
+	candidates := addressComponents
+	if component, ok := componentsByRole[part.Role]; ok {
+		candidates = []addressComponent{component}
+	}
+
+	for _, component := range candidates {
+		if part.Role == queryparser.RoleUnknown && matched[component.field] != nil {
+			continue
+		}
+		hit, err := r.matchAdminBoundary(ctx, component, part.Text)
+		if err != nil {
+			return component, nil, err
+		}
+		if hit != nil {
+			return component, hit, nil
+		}
+	}
+	return addressComponent{}, nil, nil
+}
+
+// matchAdminBoundary returns the admin boundary of a division whose name best
+// matches text, tolerating misspellings
+func (r *Resolver) matchAdminBoundary(ctx context.Context, component addressComponent, text string) (*ESHit, error) {
+	query := map[string]interface{}{
+		"size": 1,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": []map[string]interface{}{
+					{"multi_match": map[string]interface{}{
+						"query":     text,
+						"fields":    []string{component.field, component.field + "_ne", "name", "name_ne"},
+						"fuzziness": "AUTO",
+						"type":      "best_fields",
+					}},
+				},
+				"filter": []map[string]interface{}{
+					{"term": map[string]interface{}{"entity_type": "admin_boundary"}},
+					{"term": map[string]interface{}{"admin_level": component.adminLevel}},
+				},
+				"must_not": []map[string]interface{}{notDeletedClause},
+			},
+		},
+	}
+
+	esResponse, err := r.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(esResponse.Hits.Hits) == 0 {
+		return nil, nil
+	}
+	return &esResponse.Hits.Hits[0], nil
+}
+
+// namesAgree reports whether text names the boundary called name, either in
+// full or as its leading words ("Lalitpur" for "Lalitpur Metropolitan City")
+func namesAgree(name, text string) bool {
+	name, text = strings.ToLower(strings.TrimSpace(name)), strings.ToLower(strings.TrimSpace(text))
+	return text != "" && (name == text || strings.HasPrefix(name, text+" "))
+}
@@ -0,0 +1,43 @@
+package graph
+
+import (
+	"context"
+
+	"search-core/graph/model"
+)
+
+// osmTypes are the OSM element types
+var osmTypes = map[string]bool{"node": true, "way": true, "relation": true}
+
+// GetLocationByOsmID returns the location indexed for the given OSM element, or
+// nil if it is not indexed
+func (r *queryResolver) GetLocationByOsmID(ctx context.Context, osmID int64, osmType string) (*model.Location, error) {
+	if osmID <= 0 {
+		return nil, userError("osmId must be positive")
+	}
+	if !osmTypes[osmType] {
+		return nil, userError("osmType must be one of node, way or relation")
+	}
+
+	query := map[string]interface{}{
+		"size": 1,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []map[string]interface{}{
+					{"term": map[string]interface{}{"osm_id": osmID}},
+					{"term": map[string]interface{}{"osm_type.keyword": osmType}},
+				},
+				"must_not": []map[string]interface{}{notDeletedClause},
+			},
+		},
+	}
+
+	esResponse, err := r.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(esResponse.Hits.Hits) == 0 {
+		return nil, nil
+	}
+	return convertToLocation(esResponse.Hits.Hits[0]), nil
+}
@@ -0,0 +1,43 @@
+package graph
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// maxResultWindow is Elasticsearch's default index.max_result_window
+const maxResultWindow = 10000
+
+// encodeCursor turns a hit's sort values into an opaque search_after cursor
+func encodeCursor(sortValues []interface{}) *string {
+	if len(sortValues) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(sortValues)
+	if err != nil {
+		return nil
+	}
+	cursor := base64.RawURLEncoding.EncodeToString(data)
+	return &cursor
+}
+
+// decodeCursor turns a cursor back into search_after sort values
+func decodeCursor(cursor string) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, userError("Invalid pagination cursor")
+	}
+	var sortValues []interface{}
+	if err := json.Unmarshal(data, &sortValues); err != nil || len(sortValues) == 0 {
+		return nil, userError("Invalid pagination cursor")
+	}
+	return sortValues, nil
+}
+
+// nextCursor returns the cursor for the page after hits, or nil on the last page
+func nextCursor(hits []ESHit, limit int) *string {
+	if len(hits) == 0 || len(hits) < limit {
+		return nil
+	}
+	return encodeCursor(hits[len(hits)-1].Sort)
+}
@@ -0,0 +1,26 @@
+package graph
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Nepal postal codes are 5 digits, from 10xxx (Sudurpashchim) to 57xxx (Koshi)
+const (
+	minNepalPostalCode = 10000
+	maxNepalPostalCode = 57999
+)
+
+// isNepalPostalCode reports whether the query is a 5-digit Nepal postal code
+// such as 44600 (Kathmandu)
+func isNepalPostalCode(q string) bool {
+	q = strings.TrimSpace(q)
+	if len(q) != 5 {
+		return false
+	}
+	code, err := strconv.Atoi(q)
+	if err != nil {
+		return false
+	}
+	return code >= minNepalPostalCode && code <= maxNepalPostalCode
+}
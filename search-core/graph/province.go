@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"context"
+	"strings"
+
+	"search-core/graph/model"
+)
+
+// provinceCount is the number of provinces in Nepal, numbered 1 to 7
+const provinceCount = 7
+
+// provinceNumbers maps lowercased English and Nepali province names to their
+// numbers, as PROVINCE_NUMBERS does in the syncer
+var provinceNumbers = map[string]int{
+	"koshi": 1, "कोशी": 1,
+	"madhesh": 2, "मधेश": 2,
+	"bagmati": 3, "बागमती": 3,
+	"gandaki": 4, "गण्डकी": 4,
+	"lumbini": 5, "लुम्बिनी": 5,
+	"karnali": 6, "कर्णाली": 6,
+	"sudurpashchim": 7, "सुदूरपश्चिम": 7,
+}
+
+// provinceNumber returns the number of a province from the first word of its
+// name (so "Bagmati Province" is 3), or 0 if the name is unknown
+func provinceNumber(name string) int {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return 0
+	}
+	return provinceNumbers[strings.ToLower(words[0])]
+}
+
+// GetProvinceByNumber returns the province with the given number (1-7), or
+// nil if it is not indexed
+func (r *queryResolver) GetProvinceByNumber(ctx context.Context, number int) (*model.Location, error) {
+	if number < 1 || number > provinceCount {
+		return nil, userError("number must be between 1 and %d", provinceCount)
+	}
+
+	query := map[string]interface{}{
+		"size": 1,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []map[string]interface{}{
+					{"term": map[string]interface{}{"entity_type": "admin_boundary"}},
+					{"term": map[string]interface{}{"admin_level": adminLevelProvince}},
+					{"term": map[string]interface{}{"province_number": number}},
+				},
+				"must_not": []map[string]interface{}{notDeletedClause},
+			},
+		},
+	}
+
+	esResponse, err := r.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(esResponse.Hits.Hits) == 0 {
+		return nil, nil
+	}
+	return convertToLocation(esResponse.Hits.Hits[0]), nil
+}
@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"sort"
+
+	"search-core/graph/model"
+)
+
+// Ranker reorders search results after Elasticsearch returns them. hits and
+// results are parallel slices; implementations may update result scores.
+type Ranker interface {
+	Rank(hits []ESHit, results []*model.Location) []*model.Location
+}
+
+// BoostScoreRanker orders results by ES score multiplied by the document's
+// stored boost_score. Documents without a boost_score keep their ES score.
+type BoostScoreRanker struct{}
+
+// Rank implements Ranker
+func (BoostScoreRanker) Rank(hits []ESHit, results []*model.Location) []*model.Location {
+	for i, loc := range results {
+		if i >= len(hits) {
+			break
+		}
+		if boost := hits[i].Source.BoostScore; boost > 0 {
+			loc.Score = hits[i].Score * boost
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// rankResults applies the configured ranker to relevance-sorted results;
+// explicit sort modes keep Elasticsearch's order
+func (r *Resolver) rankResults(input model.LocationSearchInput, hits []ESHit, results []*model.Location) []*model.Location {
+	if !r.reranks(input) {
+		return results
+	}
+	return r.Ranker.Rank(hits, results)
+}
+
+// reranks reports whether the configured ranker applies to the search. A
+// variant that orders by boost_score in Elasticsearch keeps that order.
+func (r *Resolver) reranks(input model.LocationSearchInput) bool {
+	if variant := r.rankingVariant(input); variant != nil && variant.BoostScoreFirst {
+		return false
+	}
+	return r.Ranker != nil && (input.SortBy == nil || *input.SortBy == model.LocationSortModeRelevance)
+}
@@ -0,0 +1,172 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"search-core/graph/model"
+)
+
+// reindexJobsIndex holds partial reindex jobs. The syncer, run with
+// PROCESS_REINDEX_JOBS=true, picks up pending jobs and updates their status.
+const reindexJobsIndex = "nepal_reindex_jobs"
+
+// reindexStatusPending is the status of a queued job; the syncer moves it on
+// to running and then completed or failed
+const reindexStatusPending = "pending"
+
+// provinceBBoxesJSON holds approximate bounding boxes of Nepal's provinces,
+// keyed by lowercased English name
+//
+//go:embed province_bboxes.json
+var provinceBBoxesJSON []byte
+
+// boundingBox is a WGS84 bounding box in degrees
+type boundingBox struct {
+	North float64 `json:"north"`
+	South float64 `json:"south"`
+	East  float64 `json:"east"`
+	West  float64 `json:"west"`
+}
+
+var provinceBBoxes = loadProvinceBBoxes(provinceBBoxesJSON)
+
+func loadProvinceBBoxes(data []byte) map[string]boundingBox {
+	var boxes map[string]boundingBox
+	if err := json.Unmarshal(data, &boxes); err != nil {
+		log.Fatalf("Error parsing province_bboxes.json: %v", err)
+	}
+	return boxes
+}
+
+// reindexJob is a partial reindex request as stored in the jobs index
+type reindexJob struct {
+	JobID              string      `json:"job_id"`
+	Province           string      `json:"province"`
+	BBox               boundingBox `json:"bbox"`
+	Status             string      `json:"status"`
+	EstimatedDocuments int         `json:"estimated_documents"`
+	IndexedDocuments   *int        `json:"indexed_documents,omitempty"`
+	Error              string      `json:"error,omitempty"`
+	RequestedBy        string      `json:"requested_by"`
+	RequestedAt        time.Time   `json:"requested_at"`
+	CompletedAt        *time.Time  `json:"completed_at,omitempty"`
+}
+
+// ReindexFromOsm queues a reindex of the OSM features inside a province's
+// bounding box. The estimate is the number of documents currently indexed there.
+func (r *mutationResolver) ReindexFromOsm(ctx context.Context, province string) (*model.ReindexStatus, error) {
+	key := strings.ToLower(strings.TrimSpace(province))
+	bbox, ok := provinceBBoxes[key]
+	if !ok {
+		return nil, userError("Unknown province %q", province)
+	}
+
+	estimated, err := r.countInBoundingBox(ctx, bbox)
+	if err != nil {
+		return nil, err
+	}
+
+	job := reindexJob{
+		JobID:              uuid.NewString(),
+		Province:           key,
+		BBox:               bbox,
+		Status:             reindexStatusPending,
+		EstimatedDocuments: estimated,
+		RequestedBy:        ActorFromContext(ctx),
+		RequestedAt:        time.Now().UTC(),
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(job); err != nil {
+		return nil, fmt.Errorf("error encoding reindex job: %w", err)
+	}
+
+	res, err := r.ESClient.Index(
+		reindexJobsIndex, &buf,
+		r.ESClient.Index.WithContext(ctx),
+		r.ESClient.Index.WithDocumentID(job.JobID),
+		r.ESClient.Index.WithRefresh("wait_for"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error indexing reindex job: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+
+	return convertReindexJob(job), nil
+}
+
+// GetReindexStatus returns a reindex job's progress, or nil if it does not exist
+func (r *queryResolver) GetReindexStatus(ctx context.Context, jobID string) (*model.ReindexStatus, error) {
+	res, err := r.ESClient.Get(reindexJobsIndex, jobID, r.ESClient.Get.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching reindex job: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == 404 {
+		return nil, nil
+	}
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+
+	var doc struct {
+		Source reindexJob `json:"_source"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+	return convertReindexJob(doc.Source), nil
+}
+
+// countInBoundingBox counts indexed locations inside bbox
+func (r *Resolver) countInBoundingBox(ctx context.Context, bbox boundingBox) (int, error) {
+	esResponse, err := r.search(ctx, map[string]interface{}{
+		"size": 0,
+		"query": map[string]interface{}{
+			"geo_bounding_box": map[string]interface{}{
+				"location": map[string]interface{}{
+					"top_left":     map[string]float64{"lat": bbox.North, "lon": bbox.West},
+					"bottom_right": map[string]float64{"lat": bbox.South, "lon": bbox.East},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return esResponse.Hits.Total.Value, nil
+}
+
+// convertReindexJob converts a stored reindex job to its GraphQL status
+func convertReindexJob(job reindexJob) *model.ReindexStatus {
+	status := &model.ReindexStatus{
+		JobID:              job.JobID,
+		Province:           job.Province,
+		Status:             job.Status,
+		EstimatedDocuments: job.EstimatedDocuments,
+		IndexedDocuments:   job.IndexedDocuments,
+		Error:              optionalStr(job.Error),
+		RequestedAt:        job.RequestedAt.Format(time.RFC3339),
+	}
+	if job.CompletedAt != nil {
+		status.CompletedAt = strPtr(job.CompletedAt.Format(time.RFC3339))
+	}
+	return status
+}
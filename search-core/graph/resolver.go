@@ -1,14 +1,102 @@
 package graph
 
 import (
+	"net/http"
+	"time"
+
 	elasticsearch "github.com/elastic/go-elasticsearch/v8"
+	"github.com/redis/go-redis/v9"
+
+	"search-core/graph/model"
 )
 
 type Resolver struct {
 	ESClient *elasticsearch.Client
+
+	// ESTransport is the HTTP transport the ES client was built with; Close
+	// releases its idle connections
+	ESTransport http.RoundTripper
+
+	// Cache stores search responses; nil disables caching
+	Cache    *FallbackCache
+	CacheTTL time.Duration
+
+	// SessionStore keeps per-session recent searches; nil disables the feature
+	SessionStore SessionStore
+
+	// SearchLogger records search analytics; nil disables logging
+	SearchLogger *SearchLogger
+
+	// AuditLogger records location mutations; nil disables auditing
+	AuditLogger *AuditLogger
+
+	// PubSub receives the syncer's location update events; nil disables subscriptions
+	PubSub *redis.Client
+
+	// DefaultCountry is the country code applied when the input has none
+	DefaultCountry string
+
+	// DebugFeatures enables debugging options such as explain mode
+	DebugFeatures bool
+
+	// MinQueryLength is the minimum number of characters in a search query
+	MinQueryLength int
+
+	// MaxQueryLength is the maximum number of characters in a search query;
+	// zero means no limit
+	MaxQueryLength int
+
+	// RoutingOptimization routes province-filtered searches to the shards
+	// holding that province; requires an index built with ENABLE_ROUTING_OPTIMIZATION
+	RoutingOptimization bool
+
+	// FieldBoosts overrides the default boosts of searched text fields
+	// (see ParseFieldBoosts); nil keeps the defaults
+	FieldBoosts map[string]float64
+
+	// SearchVariants holds the ranking of each A/B test search variant (see
+	// ParseSearchVariants); a variant without an entry uses the defaults
+	SearchVariants map[model.SearchVariant]*RankingVariant
+
+	// AmbiguityScoreGapPercent is the largest score gap between the top two
+	// results, as a percentage of the top score, for which runner-up results
+	// are returned as alternatives; zero disables alternatives
+	AmbiguityScoreGapPercent int
+
+	// Ranker reorders relevance-sorted search results; nil keeps ES order
+	Ranker Ranker
+
+	// StalenessDecayScale is the Elasticsearch duration (e.g. "365d") over
+	// which the score of features not edited in OSM for two years decays by
+	// half; empty disables the decay
+	StalenessDecayScale string
+
+	// SearchMaxDuration bounds a search when the request has no earlier
+	// deadline; zero means no limit beyond the request's own
+	SearchMaxDuration time.Duration
+
+	indexVersion indexVersionCache
+}
+
+// Close releases the Elasticsearch client's idle connections
+func (r *Resolver) Close() error {
+	if transport, ok := r.ESTransport.(*http.Transport); ok {
+		transport.CloseIdleConnections()
+	}
+	return nil
 }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
 type queryResolver struct{ *Resolver }
+
+type mutationResolver struct{ *Resolver }
+
+type subscriptionResolver struct{ *Resolver }
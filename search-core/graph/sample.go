@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"search-core/graph/model"
+)
+
+// sampleSearch returns up to limit random documents for data quality spot
+// checks. The query text is ignored; only entityType narrows the sample.
+func (r *queryResolver) sampleSearch(ctx context.Context, input model.LocationSearchInput) (*model.LocationSearchResponse, error) {
+	limit := resolveLimit(input.Limit)
+
+	var filter []map[string]interface{}
+	if input.EntityType != nil && *input.EntityType != "" {
+		filter = append(filter, map[string]interface{}{
+			"term": map[string]interface{}{"entity_type": *input.EntityType},
+		})
+	}
+
+	boolQuery := map[string]interface{}{"filter": filter}
+	if !includeDeleted(input) {
+		boolQuery["must_not"] = []map[string]interface{}{notDeletedClause}
+	}
+
+	query := map[string]interface{}{
+		"size": limit,
+		"query": map[string]interface{}{
+			"function_score": map[string]interface{}{
+				"query": map[string]interface{}{
+					"bool": boolQuery,
+				},
+				"random_score": map[string]interface{}{
+					"seed":  time.Now().UnixNano(),
+					"field": "_seq_no",
+				},
+				"boost_mode": "replace",
+			},
+		},
+	}
+
+	esResponse, err := r.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := convertHits(esResponse.Hits.Hits)
+	if len(input.Fields) > 0 {
+		for _, loc := range results {
+			maskLocation(loc, input.Fields)
+		}
+	}
+
+	return &model.LocationSearchResponse{
+		Results:  results,
+		Total:    esResponse.Hits.Total.Value,
+		Took:     esResponse.Took,
+		MaxScore: esResponse.Hits.MaxScore,
+		Clusters: []*model.GeohashCluster{},
+
+		QueryInterpretation: strPtr("Random sample"),
+	}, nil
+}
@@ -3,40 +3,332 @@ package graph
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
+	"net/http"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/google/uuid"
 
 	"search-core/graph/model"
+	"search-core/internal/normalize"
+	"search-core/internal/queryparser"
 )
 
-// SearchLocations performs fuzzy search with optional parent validation
+// locationIndex is the Elasticsearch index holding Nepal locations
+const locationIndex = "nepal_locations"
+
+// maxExcludeIDs caps the location IDs a search can exclude
+const maxExcludeIDs = 100
+
+// SearchLocations performs fuzzy search with optional parent validation. Each
+// call gets a search ID, returned in the response and the X-Search-ID header,
+// that clients can quote in bug reports to find the matching server logs.
 func (r *queryResolver) SearchLocation(ctx context.Context, input model.LocationSearchInput) (*model.LocationSearchResponse, error) {
-	// Set default limit
-	limit := 10
-	if input.Limit != nil && *input.Limit > 0 {
-		limit = *input.Limit
-		if limit > 50 {
-			limit = 50
+	searchID := uuid.NewString()
+	addResponseHeader(ctx, "X-Search-ID", searchID)
+	if input.SearchVariant != nil {
+		log.Printf("Search %s uses variant %s", searchID, strings.ToLower(string(*input.SearchVariant)))
+	}
+
+	response, err := r.searchLocation(ctx, input, searchID)
+	if err != nil {
+		log.Printf("Search %s failed: %v", searchID, err)
+		return nil, err
+	}
+	response.SearchID = &searchID
+	response.IndexVersion = r.currentIndexVersion(ctx)
+	return response, nil
+}
+
+// searchLocation validates and normalizes the input, then runs the search
+func (r *queryResolver) searchLocation(ctx context.Context, input model.LocationSearchInput, searchID string) (*model.LocationSearchResponse, error) {
+	input.Query = normalize.SanitizeQuery(input.Query)
+
+	ctx, cancel := searchDeadline(ctx, r.SearchMaxDuration)
+	defer cancel()
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("search deadline exceeded before execution: %w", err)
+	}
+
+	// Samples are random by design, so they skip query validation and the cache
+	if input.Sample != nil && *input.Sample {
+		return r.sampleSearch(ctx, input)
+	}
+
+	// Characters, not bytes, so Devanagari queries are measured fairly
+	queryLength := utf8.RuneCountInString(strings.TrimSpace(input.Query))
+	if r.MaxQueryLength > 0 && queryLength > r.MaxQueryLength {
+		return nil, userError("Query must be at most %d characters", r.MaxQueryLength)
+	}
+	if queryLength < r.MinQueryLength {
+		return nil, userError("Query must be at least %d characters", r.MinQueryLength)
+	}
+
+	input = r.normalizeInput(input)
+
+	response, err := r.cachedSearch(ctx, input)
+	if isUnavailable(err) {
+		log.Printf("WARNING: search %s falling back to static gazetteer: %v", searchID, err)
+		response = gazetteerSearch(input)
+		response.IsFallback = boolPtr(true)
+		response.ParsedFilters = echoInput(input)
+		return response, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	response.IsFallback = boolPtr(false)
+	response.ParsedFilters = echoInput(input)
+
+	if r.SearchLogger != nil {
+		r.SearchLogger.Log(searchID, input, response)
+	}
+
+	return response, nil
+}
+
+// normalizeInput fills in defaults and extracts structure from a sanitized,
+// validated search input before it is turned into a query
+func (r *Resolver) normalizeInput(input model.LocationSearchInput) model.LocationSearchInput {
+	input.Query = normalize.DevanagariNumerals(input.Query)
+
+	// Pull "Ward N" out of the free text unless wards were given explicitly
+	if input.Ward == nil && len(input.Wards) == 0 {
+		if cleaned, ward := queryparser.ParseQueryForWard(input.Query); ward != nil {
+			input.Query = cleaned
+			input.Ward = ward
+		}
+	}
+
+	if input.Country == nil || *input.Country == "" {
+		input.Country = strPtr(r.DefaultCountry)
+	}
+	input.Municipality = trimFilter(input.Municipality)
+	input.Municipalities = trimFilters(input.Municipalities)
+	input.District = trimFilter(input.District)
+	input.Province = trimFilter(input.Province)
+	return input
+}
+
+// searchDeadline keeps the request's own deadline when it falls within
+// maxDuration, and otherwise imposes maxDuration as the fallback deadline
+func searchDeadline(ctx context.Context, maxDuration time.Duration) (context.Context, context.CancelFunc) {
+	if maxDuration <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= maxDuration {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, maxDuration)
+}
+
+// cachedSearch serves the search from the cache when one is configured
+func (r *queryResolver) cachedSearch(ctx context.Context, input model.LocationSearchInput) (*model.LocationSearchResponse, error) {
+	if r.Cache == nil {
+		return r.executeSearch(ctx, input)
+	}
+
+	// A response cached without match confidence cannot answer a request selecting it
+	key := searchCacheKey(input)
+	if selectsField(ctx, "matchConfidence") {
+		key += ":confidence"
+	}
+	cached, err := r.Cache.Fetch(ctx, key, r.CacheTTL, func() ([]byte, error) {
+		response, err := r.executeSearch(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(response)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response model.LocationSearchResponse
+	if err := json.Unmarshal(cached, &response); err != nil {
+		return nil, fmt.Errorf("error decoding cached response: %w", err)
+	}
+	return &response, nil
+}
+
+// executeSearch runs the search against Elasticsearch
+func (r *queryResolver) executeSearch(ctx context.Context, input model.LocationSearchInput) (*model.LocationSearchResponse, error) {
+	limit := resolveLimit(input.Limit)
+
+	if input.OsmID != nil {
+		if _, err := strconv.ParseInt(*input.OsmID, 10, 64); err != nil {
+			return nil, userError("osmId must be a numeric OSM element ID")
+		}
+	}
+	if input.Ward != nil && len(input.Wards) > 0 {
+		return nil, userError("ward and wards cannot be used together")
+	}
+	if len(input.ExcludeIds) > maxExcludeIDs {
+		return nil, userError("excludeIds accepts at most %d IDs", maxExcludeIDs)
+	}
+	if input.Offset != nil && input.After != nil {
+		return nil, userError("offset and after cannot be used together")
+	}
+	if input.Offset != nil && *input.Offset+limit > maxResultWindow {
+		return nil, userError("Cannot paginate beyond 10,000 results. Use cursor-based pagination instead.")
+	}
+	if input.SortBy != nil && *input.SortBy == model.LocationSortModeDistance && input.NearPoint == nil {
+		return nil, userError("sortBy DISTANCE requires nearPoint")
+	}
+	if input.ClusterByGeohash != nil {
+		return r.clusterSearch(ctx, input, limit)
+	}
+
+	query, err := r.prepareQuery(input, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	esResponse, err := r.searchRouted(ctx, query, r.provinceRouting(input))
+	if err != nil {
+		return nil, err
+	}
+
+	// Optionally widen a search that found nothing by dropping filters
+	searched := input
+	var relaxedFilters []string
+	if input.EnableFallbackSearch != nil && *input.EnableFallbackSearch {
+		esResponse, relaxedFilters, err = r.searchWithFallback(ctx, &searched, limit, esResponse)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Convert to GraphQL response
+	topExplanation := r.topResultExplanation(ctx, searched, limit, esResponse.Hits.Hits)
+	results := convertHits(esResponse.Hits.Hits)
+	// Confidence costs an _explain request, so it is only computed when selected
+	if selectsField(ctx, "matchConfidence") {
+		applyMatchConfidence(input.Query, results, esResponse.Hits.Hits, r.maxPossibleScore(ctx, searched, limit, esResponse.Hits.Hits, topExplanation))
+	}
+	if r.showFieldScores(input) {
+		explained := input.Explain != nil && *input.Explain
+		for i, loc := range results {
+			loc.FieldScores = fieldScores(esResponse.Hits.Hits[i], r.reranks(input))
+			if !explained {
+				loc.ScoreExplanation = nil
+			}
+		}
+	}
+	results = r.rankResults(input, esResponse.Hits.Hits, results)
+
+	// Re-ranked scores replace ES scores, so the maximum must come from them
+	maxScore := esResponse.Hits.MaxScore
+	if r.reranks(input) && len(results) > 0 {
+		maxScore = &results[0].Score
+	}
+	neighbors := r.neighboringMunicipalities(ctx, esResponse.Hits.Hits, results)
+
+	// Perform validation if parent filters provided
+	validation := performValidation(input, results)
+
+	if len(input.Fields) > 0 {
+		for _, loc := range results {
+			maskLocation(loc, input.Fields)
 		}
+		for _, loc := range neighbors {
+			maskLocation(loc, input.Fields)
+		}
+	}
+
+	// Scores are only comparable when results are sorted by relevance
+	var alternatives []*model.Location
+	if input.SortBy == nil || *input.SortBy == model.LocationSortModeRelevance {
+		alternatives = ambiguousAlternatives(results, r.AmbiguityScoreGapPercent)
 	}
 
-	// Build Elasticsearch query
-	query := buildSearchQuery(input, limit)
+	response := &model.LocationSearchResponse{
+		Results:      results,
+		Alternatives: alternatives,
+		Neighbors:    neighbors,
+		Total:        esResponse.Hits.Total.Value,
+		Took:         esResponse.Took,
+		MaxScore:     maxScore,
+		NextCursor:   nextCursor(esResponse.Hits.Hits, limit),
+		Validation:   validation,
+		Clusters:     []*model.GeohashCluster{},
+
+		QueryInterpretation:  strPtr(describeInterpretation(searched)),
+		RelaxedFilters:       relaxedFilters,
+		QueryProfile:         rawJSONToStr(esResponse.Profile),
+		TopResultExplanation: rawJSONToStr(topExplanation),
+	}
+
+	return response, nil
+}
+
+// prepareQuery builds the Elasticsearch request body for a search input
+func (r *queryResolver) prepareQuery(input model.LocationSearchInput, limit int) (map[string]interface{}, error) {
+	query := r.buildSearchQuery(input, limit)
+	query["query"] = r.withStalenessDecay(query["query"])
+	if input.After != nil {
+		searchAfter, err := decodeCursor(*input.After)
+		if err != nil {
+			return nil, err
+		}
+		query["search_after"] = searchAfter
+	}
+	// Field scores are derived from the explanation
+	if (r.DebugFeatures && input.Explain != nil && *input.Explain) || r.showFieldScores(input) {
+		query["explain"] = true
+	}
+	if r.DebugFeatures && input.ProfileQuery != nil && *input.ProfileQuery {
+		query["profile"] = true
+	}
+	if len(input.Fields) > 0 {
+		includes, err := sourceIncludes(input.Fields)
+		if err != nil {
+			return nil, err
+		}
+		query["_source"] = map[string]interface{}{"includes": includes}
+	}
+	return query, nil
+}
+
+// search executes an Elasticsearch query against the locations index
+func (r *Resolver) search(ctx context.Context, query map[string]interface{}) (*ElasticsearchResponse, error) {
+	return r.searchRouted(ctx, query, "")
+}
+
+// searchRouted executes a query restricted to the shards for routing; an
+// empty routing searches all shards
+func (r *Resolver) searchRouted(ctx context.Context, query map[string]interface{}, routing string) (*ElasticsearchResponse, error) {
+	// Boundary polygons are only used for containment queries, never returned
+	if _, ok := query["_source"]; !ok {
+		query["_source"] = map[string]interface{}{"excludes": []string{"boundary"}}
+	}
 
-	// Execute search
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(query); err != nil {
 		return nil, fmt.Errorf("error encoding query: %w", err)
 	}
 
-	res, err := r.ESClient.Search(
+	opts := []func(*esapi.SearchRequest){
 		r.ESClient.Search.WithContext(ctx),
-		r.ESClient.Search.WithIndex("nepal_locations"),
+		r.ESClient.Search.WithIndex(locationIndex),
 		r.ESClient.Search.WithBody(&buf),
 		r.ESClient.Search.WithTrackTotalHits(true),
-	)
+	}
+	if routing != "" {
+		opts = append(opts, r.ESClient.Search.WithRouting(routing))
+	}
+
+	res, err := r.ESClient.Search(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("error executing search: %w", err)
 	}
@@ -53,24 +345,76 @@ func (r *queryResolver) SearchLocation(ctx context.Context, input model.Location
 		return nil, fmt.Errorf("error parsing response: %w", err)
 	}
 
-	// Convert to GraphQL response
-	results := make([]*model.Location, 0, len(esResponse.Hits.Hits))
-	for _, hit := range esResponse.Hits.Hits {
-		loc := convertToLocation(hit)
-		results = append(results, loc)
+	return &esResponse, nil
+}
+
+// getLocation fetches a single location document by ID, returning nil if it does not exist
+func (r *Resolver) getLocation(ctx context.Context, id string) (*ESHit, error) {
+	// Routed documents are not on the shard a plain GET by ID would read
+	if r.RoutingOptimization {
+		esResponse, err := r.search(ctx, map[string]interface{}{
+			"size":  1,
+			"query": map[string]interface{}{"ids": map[string]interface{}{"values": []string{id}}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(esResponse.Hits.Hits) == 0 {
+			return nil, nil
+		}
+		return &esResponse.Hits.Hits[0], nil
 	}
 
-	// Perform validation if parent filters provided
-	validation := performValidation(input, results)
+	res, err := r.ESClient.Get(locationIndex, id,
+		r.ESClient.Get.WithContext(ctx),
+		r.ESClient.Get.WithSourceExcludes("boundary"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching location: %w", err)
+	}
+	defer res.Body.Close()
 
-	response := &model.LocationSearchResponse{
-		Results:    results,
-		Total:      esResponse.Hits.Total.Value,
-		Took:       esResponse.Took,
-		Validation: validation,
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
 	}
 
-	return response, nil
+	var hit ESHit
+	if err := json.NewDecoder(res.Body).Decode(&hit); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	return &hit, nil
+}
+
+// provinceRouting returns the routing key for a search filtered by province,
+// matching the key the syncer indexes with. Only English province names are
+// routed since documents are routed by their English province.
+func (r *Resolver) provinceRouting(input model.LocationSearchInput) string {
+	if !r.RoutingOptimization || input.Province == nil {
+		return ""
+	}
+	province := provinceRoutingKey(*input.Province)
+	for _, c := range province {
+		if c > unicode.MaxASCII {
+			return ""
+		}
+	}
+	return province
+}
+
+// resolveLimit applies the default (10) and maximum (50) result limits
+func resolveLimit(limit *int) int {
+	if limit == nil || *limit <= 0 {
+		return 10
+	}
+	if *limit > 50 {
+		return 50
+	}
+	return *limit
 }
 
 // Health check resolver
@@ -95,19 +439,59 @@ func (r *queryResolver) Health(ctx context.Context) (*model.HealthStatus, error)
 }
 
 // buildSearchQuery creates Elasticsearch query with fuzzy matching
-func buildSearchQuery(input model.LocationSearchInput, limit int) map[string]interface{} {
-	// Build multi-match query with fuzzy search
-	mustClauses := []map[string]interface{}{
-		{
-			"multi_match": map[string]interface{}{
-				"query":     input.Query,
-				"fields":    []string{"name^3", "name_ne^3", "name_en^3", "name.fuzzy^2", "name_ne.fuzzy^2", "name_en.fuzzy^2", "search_text"},
-				"fuzziness": "AUTO",
-				"type":      "best_fields",
+func (r *Resolver) buildSearchQuery(input model.LocationSearchInput, limit int) map[string]interface{} {
+	// Build multi-match query with fuzzy search (or prefix matching for
+	// autocomplete); postal codes are matched exactly
+	prefixMode := input.SearchMode != nil && *input.SearchMode == model.SearchModePrefix
+	variant := r.rankingVariant(input)
+	matchClause := r.textMatchClause
+	if prefixMode {
+		matchClause = r.prefixMatchClause
+	}
+
+	textClause := matchClause(input.Query, 1, variant)
+	if isNepalPostalCode(input.Query) {
+		textClause = map[string]interface{}{
+			"term": map[string]interface{}{
+				"postal_code": strings.TrimSpace(input.Query),
 			},
-		},
+		}
 	}
 
+	// Nepali queries also match names that differ only by commonly confused
+	// sounds (e.g. क/ग), at a lower weight than the names as written
+	if !prefixMode && normalize.HasDevanagari(input.Query) {
+		textClause = map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should": []map[string]interface{}{
+					textClause,
+					{"match": map[string]interface{}{
+						"name_ne_phonetic": map[string]interface{}{
+							"query": normalize.NepaliPhonemes(input.Query),
+							"boost": 0.5,
+						},
+					}},
+				},
+				"minimum_should_match": 1,
+			},
+		}
+	}
+
+	// Known aliases (e.g. "Province No. 3") also match the canonical name, ranked above the alias
+	if canonical, ok := lookupSynonym(input.Query); ok {
+		textClause = map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should": []map[string]interface{}{
+					matchClause(canonical, 2, variant),
+					textClause,
+				},
+				"minimum_should_match": 1,
+			},
+		}
+	}
+
+	mustClauses := []map[string]interface{}{textClause}
+
 	// Add parent filters if provided (for validation)
 	if input.Ward != nil {
 		mustClauses = append(mustClauses, map[string]interface{}{
@@ -116,59 +500,233 @@ func buildSearchQuery(input model.LocationSearchInput, limit int) map[string]int
 			},
 		})
 	}
+	if len(input.Wards) > 0 {
+		mustClauses = append(mustClauses, map[string]interface{}{
+			"terms": map[string]interface{}{
+				"ward": input.Wards,
+			},
+		})
+	}
+	if input.OsmID != nil {
+		mustClauses = append(mustClauses, map[string]interface{}{
+			"term": map[string]interface{}{
+				"osm_id": *input.OsmID,
+			},
+		})
+	}
+	if input.EntityType != nil && *input.EntityType != "" {
+		mustClauses = append(mustClauses, map[string]interface{}{
+			"term": map[string]interface{}{
+				"entity_type": *input.EntityType,
+			},
+		})
+	}
 
-	if input.Municipality != nil && *input.Municipality != "" {
+	// A municipality list is merged with the single municipality filter
+	if len(input.Municipalities) > 0 {
+		names := append([]string{}, input.Municipalities...)
+		if input.Municipality != nil && *input.Municipality != "" {
+			names = append(names, *input.Municipality)
+		}
 		mustClauses = append(mustClauses, map[string]interface{}{
-			"multi_match": map[string]interface{}{
-				"query":  *input.Municipality,
-				"fields": []string{"municipality.keyword", "municipality_ne.keyword"},
-				"type":   "best_fields",
+			"bool": map[string]interface{}{
+				"should": []map[string]interface{}{
+					{"terms": map[string]interface{}{"municipality.keyword": names}},
+					{"terms": map[string]interface{}{"municipality_ne.keyword": names}},
+				},
+				"minimum_should_match": 1,
 			},
 		})
+	} else if input.Municipality != nil && *input.Municipality != "" {
+		mustClauses = append(mustClauses, exactNameClause(*input.Municipality, "municipality.keyword", "municipality_ne.keyword"))
 	}
 
 	if input.District != nil && *input.District != "" {
+		mustClauses = append(mustClauses, exactNameClause(*input.District, "district.keyword", "district_ne.keyword"))
+	}
+
+	if input.Province != nil && *input.Province != "" {
+		mustClauses = append(mustClauses, exactNameClause(*input.Province, "province.keyword", "province_ne.keyword"))
+	}
+
+	if input.ProvinceNumber != nil {
 		mustClauses = append(mustClauses, map[string]interface{}{
-			"multi_match": map[string]interface{}{
-				"query":  *input.District,
-				"fields": []string{"district.keyword", "district_ne.keyword"},
-				"type":   "best_fields",
+			"term": map[string]interface{}{
+				"province_number": *input.ProvinceNumber,
 			},
 		})
 	}
 
-	if input.Province != nil && *input.Province != "" {
+	if input.Country != nil && *input.Country != "" {
 		mustClauses = append(mustClauses, map[string]interface{}{
-			"multi_match": map[string]interface{}{
-				"query":  *input.Province,
-				"fields": []string{"province.keyword", "province_ne.keyword"},
-				"type":   "best_fields",
+			"terms": map[string]interface{}{
+				"country": countryValues(*input.Country),
 			},
 		})
 	}
 
+	var mustNotClauses []map[string]interface{}
+	if !includeDeleted(input) {
+		mustNotClauses = append(mustNotClauses, notDeletedClause)
+	}
+	if len(input.ExcludeIds) > 0 {
+		mustNotClauses = append(mustNotClauses, map[string]interface{}{
+			"ids": map[string]interface{}{
+				"values": input.ExcludeIds,
+			},
+		})
+	}
+
+	boolQuery := map[string]interface{}{
+		"must": mustClauses,
+	}
+	if len(mustNotClauses) > 0 {
+		boolQuery["must_not"] = mustNotClauses
+	}
+
 	query := map[string]interface{}{
 		"size": limit,
 		"query": map[string]interface{}{
-			"bool": map[string]interface{}{
-				"must": mustClauses,
-			},
+			"bool": boolQuery,
 		},
-		"sort": []map[string]interface{}{
-			{
-				"_score": map[string]interface{}{
-					"order": "desc",
-				},
-			},
-			{
-				"boost_score": map[string]interface{}{
-					"order": "desc",
+		"sort": buildSort(input, variant),
+	}
+
+	if input.Offset != nil && *input.Offset > 0 {
+		query["from"] = *input.Offset
+	}
+
+	// With an explicit sort ES only reports max_score when asked to track scores
+	if input.SortBy == nil || *input.SortBy == model.LocationSortModeRelevance {
+		query["track_scores"] = true
+	}
+
+	return query
+}
+
+// exactNameClause matches documents whose admin name in any of the keyword
+// fields equals name exactly, ignoring case and surrounding or repeated spaces
+func exactNameClause(name string, fields ...string) map[string]interface{} {
+	normalized := normalizeAdminName(name)
+	should := make([]map[string]interface{}, 0, len(fields))
+	for _, field := range fields {
+		should = append(should, map[string]interface{}{
+			"term": map[string]interface{}{
+				field: map[string]interface{}{
+					"value":            normalized,
+					"case_insensitive": true,
 				},
 			},
+		})
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"should":               should,
+			"minimum_should_match": 1,
 		},
 	}
+}
 
-	return query
+// normalizeAdminName lowercases an admin name filter and collapses its whitespace
+func normalizeAdminName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// notDeletedClause matches soft-deleted documents; searches exclude them via must_not
+var notDeletedClause = map[string]interface{}{
+	"term": map[string]interface{}{"deleted": true},
+}
+
+// includeDeleted reports whether the search should also return soft-deleted documents
+func includeDeleted(input model.LocationSearchInput) bool {
+	return input.IncludeDeleted != nil && *input.IncludeDeleted
+}
+
+// countryNames maps supported ISO country codes to the country names stored in the index
+var countryNames = map[string]string{
+	"NP": "Nepal",
+	"BT": "Bhutan",
+	"IN": "India",
+}
+
+// countryValues returns the indexed values that identify a country. Documents
+// currently store the country name, so both the code and the name are matched.
+func countryValues(code string) []string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if name, ok := countryNames[code]; ok {
+		return []string{code, name}
+	}
+	return []string{code}
+}
+
+// textMatchClause creates the fuzzy multi_match clause for free-text queries
+func (r *Resolver) textMatchClause(text string, boost float64, variant *RankingVariant) map[string]interface{} {
+	return map[string]interface{}{
+		"multi_match": map[string]interface{}{
+			"query":     text,
+			"fields":    boostedFields(textMatchFields, r.fieldBoosts(variant)),
+			"fuzziness": fuzziness(variant),
+			"type":      "best_fields",
+			"boost":     boost,
+		},
+	}
+}
+
+// prefixMatchClause matches names starting with text ("Kath" -> "Kathmandu").
+// A phrase_prefix multi_match runs match_phrase_prefix on each name field.
+func (r *Resolver) prefixMatchClause(text string, boost float64, variant *RankingVariant) map[string]interface{} {
+	return map[string]interface{}{
+		"multi_match": map[string]interface{}{
+			"query":  text,
+			"fields": boostedFields(prefixMatchFields, r.fieldBoosts(variant)),
+			"type":   "phrase_prefix",
+			"boost":  boost,
+		},
+	}
+}
+
+// buildSort creates the Elasticsearch sort clauses for the requested sort mode.
+// Every mode ends with an id tiebreaker so search_after cursors are stable.
+func buildSort(input model.LocationSearchInput, variant *RankingVariant) []map[string]interface{} {
+	mode := model.LocationSortModeRelevance
+	if input.SortBy != nil {
+		mode = *input.SortBy
+	}
+
+	var sort []map[string]interface{}
+	switch mode {
+	case model.LocationSortModeNameAsc:
+		sort = append(sort, map[string]interface{}{"name.keyword": map[string]interface{}{"order": "asc"}})
+	case model.LocationSortModeNameDesc:
+		sort = append(sort, map[string]interface{}{"name.keyword": map[string]interface{}{"order": "desc"}})
+	case model.LocationSortModeDistance:
+		if input.NearPoint != nil {
+			sort = append(sort, map[string]interface{}{
+				"_geo_distance": map[string]interface{}{
+					"location": map[string]interface{}{
+						"lat": input.NearPoint.Lat,
+						"lon": input.NearPoint.Lon,
+					},
+					"order": "asc",
+					"unit":  "m",
+				},
+			})
+		}
+	case model.LocationSortModeAdminLevelAsc:
+		sort = append(sort, map[string]interface{}{
+			"admin_level": map[string]interface{}{"order": "asc", "missing": "_last"},
+		})
+	default:
+		score := map[string]interface{}{"_score": map[string]interface{}{"order": "desc"}}
+		boostScore := map[string]interface{}{"boost_score": map[string]interface{}{"order": "desc"}}
+		if variant != nil && variant.BoostScoreFirst {
+			sort = append(sort, boostScore, score)
+		} else {
+			sort = append(sort, score, boostScore)
+		}
+	}
+
+	return append(sort, map[string]interface{}{"id": map[string]interface{}{"order": "asc"}})
 }
 
 // performValidation checks if parent filters match results
@@ -194,7 +752,7 @@ func performValidation(input model.LocationSearchInput, results []*model.Locatio
 	mismatches := []*model.ValidationMismatch{}
 	topResult := results[0]
 
-	if input.Ward != nil && topResult.Ward != input.Ward {
+	if input.Ward != nil && (topResult.Ward == nil || *topResult.Ward != *input.Ward) {
 		mismatches = append(mismatches, &model.ValidationMismatch{
 			Field:    "ward",
 			Expected: fmt.Sprintf("%d", *input.Ward),
@@ -202,7 +760,8 @@ func performValidation(input model.LocationSearchInput, results []*model.Locatio
 		})
 	}
 
-	if input.Municipality != nil && *input.Municipality != "" {
+	// Merged into a municipality list, the single filter no longer names the expected value
+	if input.Municipality != nil && *input.Municipality != "" && len(input.Municipalities) == 0 {
 		if topResult.Municipality == nil || !stringsMatch(*input.Municipality, *topResult.Municipality) {
 			mismatches = append(mismatches, &model.ValidationMismatch{
 				Field:    "municipality",
@@ -245,31 +804,60 @@ func performValidation(input model.LocationSearchInput, results []*model.Locatio
 	}
 }
 
-// convertToLocation converts ES hit to GraphQL Location
+// convertHits converts ES hits to GraphQL Locations
+func convertHits(hits []ESHit) []*model.Location {
+	results := make([]*model.Location, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, convertToLocation(hit))
+	}
+	return results
+}
+
+// convertToLocation converts ES hit to GraphQL Location. Optional fields that
+// are absent from the document (zero values) are returned as null.
 func convertToLocation(hit ESHit) *model.Location {
 	src := hit.Source
 
 	return &model.Location{
-		ID:             hit.ID,
-		EntityType:     src.EntityType,
-		Name:           src.Name,
-		NameNe:         &src.NameNe,
-		NameEn:         &src.NameEn,
-		PlaceType:      &src.PlaceType,
-		AdminLevel:     &src.AdminLevel,
-		Location:       convertGeoPoint(src.Location),
-		Ward:           &src.Ward,
-		Municipality:   &src.Municipality,
-		MunicipalityNe: &src.MunicipalityNe,
-		District:       &src.District,
-		DistrictNe:     &src.DistrictNe,
-		Province:       &src.Province,
-		ProvinceNe:     &src.ProvinceNe,
-		Country:        src.Country,
-		Score:          hit.Score,
+		ID:               hit.ID,
+		EntityType:       src.EntityType,
+		Name:             src.Name,
+		NameNe:           optionalStr(src.NameNe),
+		NameEn:           optionalStr(src.NameEn),
+		PlaceType:        optionalStr(src.PlaceType),
+		AdminLevel:       optionalInt(src.AdminLevel),
+		Location:         convertGeoPoint(src.Location),
+		Ward:             optionalInt(src.Ward),
+		Municipality:     optionalStr(src.Municipality),
+		MunicipalityNe:   optionalStr(src.MunicipalityNe),
+		MunicipalityType: optionalStr(src.MunicipalityType),
+		District:         optionalStr(src.District),
+		DistrictNe:       optionalStr(src.DistrictNe),
+		Province:         optionalStr(src.Province),
+		ProvinceNe:       optionalStr(src.ProvinceNe),
+		ProvinceNumber:   optionalInt(src.ProvinceNumber),
+		Country:          src.Country,
+		OsmID:            optionalInt64Str(src.OsmID),
+		LastUpdated:      timeToStr(src.LastUpdated),
+		MatchedTags:      matchedTags(src),
+		Score:            hit.Score,
+		ScoreExplanation: rawJSONToStr(hit.Explanation),
+		Cursor:           encodeCursor(hit.Sort),
 	}
 }
 
+// matchedTags returns the OSM tags of amenity and tourism locations; other
+// entity types omit them to keep responses compact
+func matchedTags(src ESSource) map[string]interface{} {
+	if src.EntityType != "amenity" && src.EntityType != "tourism" {
+		return nil
+	}
+	if len(src.Tags) == 0 {
+		return nil
+	}
+	return src.Tags
+}
+
 // convertGeoPoint converts ES geo_point to GraphQL GeoPoint
 func convertGeoPoint(loc ESGeoPoint) *model.GeoPoint {
 	if loc.Lat == 0 && loc.Lon == 0 {
@@ -281,11 +869,96 @@ func convertGeoPoint(loc ESGeoPoint) *model.GeoPoint {
 	}
 }
 
+// searchCacheKey derives the cache key for a search input, so semantically
+// equivalent inputs share a cache entry
+func searchCacheKey(input model.LocationSearchInput) string {
+	sum := sha256.Sum256([]byte(canonicalizeInput(input)))
+	return "search:" + hex.EncodeToString(sum[:])
+}
+
+// canonicalizeInput renders a search input as JSON with sorted keys, the query
+// lowercased and trimmed, the limit resolved, and unset, empty or false fields
+// omitted
+func canonicalizeInput(input model.LocationSearchInput) string {
+	input.Query = strings.ToLower(strings.TrimSpace(input.Query))
+	limit := resolveLimit(input.Limit)
+	input.Limit = &limit
+
+	// The input contains only plain values, so marshalling cannot fail
+	data, _ := json.Marshal(input)
+	var fields map[string]interface{}
+	json.Unmarshal(data, &fields)
+
+	for key, value := range fields {
+		switch v := value.(type) {
+		case nil:
+			delete(fields, key)
+		case string:
+			if v == "" && key != "query" {
+				delete(fields, key)
+			}
+		case bool:
+			if !v {
+				delete(fields, key)
+			}
+		case []interface{}:
+			if len(v) == 0 {
+				delete(fields, key)
+			}
+		}
+	}
+
+	// encoding/json writes map keys in sorted order
+	canonical, _ := json.Marshal(fields)
+	return string(canonical)
+}
+
 // Helper functions
 func strPtr(s string) *string {
 	return &s
 }
 
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+// strVal returns the string s points to, or "" for nil
+func strVal(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// optionalStr returns nil for an empty string
+func optionalStr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// optionalInt64Str formats a non-zero int64 as a string (JSON numbers lose
+// precision beyond 2^53), returning nil for zero
+func optionalInt64Str(i int64) *string {
+	if i == 0 {
+		return nil
+	}
+	return strPtr(strconv.FormatInt(i, 10))
+}
+
+// optionalInt returns nil for zero, which ES documents use for absent numbers
+func optionalInt(i int) *int {
+	if i == 0 {
+		return nil
+	}
+	return &i
+}
+
 func ptrToStr(s *string) *string {
 	if s == nil {
 		return strPtr("null")
@@ -300,6 +973,20 @@ func ptrIntToStr(i *int) *string {
 	return strPtr(fmt.Sprintf("%d", *i))
 }
 
+func timeToStr(t time.Time) *string {
+	if t.IsZero() {
+		return nil
+	}
+	return strPtr(t.Format(time.RFC3339))
+}
+
+func rawJSONToStr(raw json.RawMessage) *string {
+	if len(raw) == 0 {
+		return nil
+	}
+	return strPtr(string(raw))
+}
+
 func stringsMatch(a, b string) bool {
 	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
 }
@@ -311,34 +998,50 @@ type ElasticsearchResponse struct {
 		Total struct {
 			Value int `json:"value"`
 		} `json:"total"`
-		Hits []ESHit `json:"hits"`
+		MaxScore *float64 `json:"max_score"`
+		Hits     []ESHit  `json:"hits"`
 	} `json:"hits"`
+	Aggregations json.RawMessage `json:"aggregations,omitempty"`
+	Suggest      json.RawMessage `json:"suggest,omitempty"`
+	Profile      json.RawMessage `json:"profile,omitempty"`
 }
 
 type ESHit struct {
-	Index  string   `json:"_index"`
-	ID     string   `json:"_id"`
-	Score  float64  `json:"_score"`
-	Source ESSource `json:"_source"`
+	Index       string          `json:"_index"`
+	ID          string          `json:"_id"`
+	Routing     string          `json:"_routing,omitempty"`
+	Score       float64         `json:"_score"`
+	Source      ESSource        `json:"_source"`
+	Explanation json.RawMessage `json:"_explanation,omitempty"`
+	Sort        []interface{}   `json:"sort,omitempty"`
 }
 
 type ESSource struct {
-	EntityType     string     `json:"entity_type"`
-	Name           string     `json:"name"`
-	NameNe         string     `json:"name_ne"`
-	NameEn         string     `json:"name_en"`
-	PlaceType      string     `json:"place_type"`
-	AdminLevel     int        `json:"admin_level"`
-	Location       ESGeoPoint `json:"location"`
-	Ward           int        `json:"ward"`
-	Municipality   string     `json:"municipality"`
-	MunicipalityNe string     `json:"municipality_ne"`
-	District       string     `json:"district"`
-	DistrictNe     string     `json:"district_ne"`
-	Province       string     `json:"province"`
-	ProvinceNe     string     `json:"province_ne"`
-	Country        string     `json:"country"`
-	BoostScore     float64    `json:"boost_score"`
+	OsmID            int64                  `json:"osm_id"`
+	EntityType       string                 `json:"entity_type"`
+	Name             string                 `json:"name"`
+	NameNe           string                 `json:"name_ne"`
+	NameEn           string                 `json:"name_en"`
+	NameRomanized    string                 `json:"name_romanized"`
+	PlaceType        string                 `json:"place_type"`
+	AdminLevel       int                    `json:"admin_level"`
+	Location         ESGeoPoint             `json:"location"`
+	Ward             int                    `json:"ward"`
+	Municipality     string                 `json:"municipality"`
+	MunicipalityNe   string                 `json:"municipality_ne"`
+	District         string                 `json:"district"`
+	DistrictNe       string                 `json:"district_ne"`
+	Province         string                 `json:"province"`
+	ProvinceNe       string                 `json:"province_ne"`
+	ProvinceNumber   int                    `json:"province_number"`
+	Country          string                 `json:"country"`
+	PostalCode       string                 `json:"postal_code"`
+	LocalGovCode     string                 `json:"local_gov_code"`
+	MunicipalityType string                 `json:"municipality_type"`
+	IsDistrictHQ     bool                   `json:"is_district_hq"`
+	Tags             map[string]interface{} `json:"tags"`
+	BoostScore       float64                `json:"boost_score"`
+	LastUpdated      time.Time              `json:"last_updated"`
 }
 
 type ESGeoPoint struct {
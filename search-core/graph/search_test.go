@@ -0,0 +1,185 @@
+package graph
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"search-core/graph/model"
+)
+
+// update rewrites the golden files from the current output: go test ./graph -run TestBuildSearchQuery -update
+var update = flag.Bool("update", false, "rewrite golden files")
+
+//go:embed testdata/search_query/*.json
+var searchQueryGoldens embed.FS
+
+func TestBuildSearchQuery(t *testing.T) {
+	treatment := model.SearchVariantTreatment
+	variants := map[model.SearchVariant]*RankingVariant{
+		model.SearchVariantTreatment: {Fuzziness: "1"},
+	}
+
+	tests := []struct {
+		name  string
+		input model.LocationSearchInput
+	}{
+		{"text_only", model.LocationSearchInput{Query: "Patan"}},
+		{"text_and_ward", model.LocationSearchInput{Query: "Patan", Ward: intPtr(5)}},
+		{"text_and_all_filters", model.LocationSearchInput{
+			Query:          "Patan",
+			Ward:           intPtr(5),
+			Municipality:   strPtr("Lalitpur"),
+			District:       strPtr("Lalitpur"),
+			Province:       strPtr("Bagmati"),
+			ProvinceNumber: intPtr(3),
+			OsmID:          strPtr("123456"),
+			EntityType:     strPtr("place"),
+			Country:        strPtr("NP"),
+		}},
+		{"five_wards", model.LocationSearchInput{Query: "Patan", Wards: []int{1, 2, 3, 4, 5}}},
+		{"municipalities", model.LocationSearchInput{Query: "Chowk", Municipalities: []string{"Kathmandu", "Lalitpur", "Bhaktapur"}}},
+		{"municipalities_merged", model.LocationSearchInput{
+			Query:          "Chowk",
+			Municipality:   strPtr("Kirtipur"),
+			Municipalities: []string{"Kathmandu", "Lalitpur"},
+		}},
+		{"municipality_exact", model.LocationSearchInput{Query: "Ason", Municipality: strPtr("  kathmandu   METROPOLITAN ")}},
+		{"empty_query", model.LocationSearchInput{Query: ""}},
+		{"limit_capping", model.LocationSearchInput{Query: "Patan", Limit: intPtr(500)}},
+		{"fuzziness_override", model.LocationSearchInput{Query: "Patan", SearchVariant: &treatment}},
+	}
+
+	r := &Resolver{SearchVariants: variants}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.MarshalIndent(r.buildSearchQuery(tt.input, resolveLimit(tt.input.Limit)), "", "  ")
+			if err != nil {
+				t.Fatalf("error encoding query: %v", err)
+			}
+
+			name := "testdata/search_query/" + tt.name + ".json"
+			if *update {
+				if err := os.WriteFile(filepath.FromSlash(name), append(got, '\n'), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := searchQueryGoldens.ReadFile(name)
+			if err != nil {
+				t.Fatalf("missing golden file (run with -update): %v", err)
+			}
+			assertSameJSON(t, got, want)
+		})
+	}
+}
+
+func TestExecuteSearchWardAndWards(t *testing.T) {
+	r := &queryResolver{&Resolver{}}
+	_, err := r.executeSearch(context.Background(), model.LocationSearchInput{
+		Query: "patan",
+		Ward:  intPtr(3),
+		Wards: []int{1, 2, 3, 4, 5},
+	})
+
+	var gqlErr *gqlerror.Error
+	if !errors.As(err, &gqlErr) || gqlErr.Message != "ward and wards cannot be used together" {
+		t.Fatalf("error = %v, want the ward and wards user error", err)
+	}
+}
+
+func TestExactNameClause(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Kathmandu", "kathmandu"},
+		{"  Kathmandu  ", "kathmandu"},
+		{"Kathmandu   Metropolitan", "kathmandu metropolitan"},
+		{"KATHMANDU", "kathmandu"},
+		{"काठमाडौं", "काठमाडौं"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clause := exactNameClause(tt.name, "municipality.keyword", "municipality_ne.keyword")
+			should := clause["bool"].(map[string]interface{})["should"].([]map[string]interface{})
+			if len(should) != 2 {
+				t.Fatalf("got %d should clauses, want 2", len(should))
+			}
+			for i, field := range []string{"municipality.keyword", "municipality_ne.keyword"} {
+				// Only exact terms: a match or fuzzy query would let "Kathmandu"
+				// also select municipalities that merely contain the word
+				term, ok := should[i]["term"].(map[string]interface{})
+				if !ok || len(should[i]) != 1 {
+					t.Fatalf("clause %d = %v, want a single term query", i, should[i])
+				}
+				want := map[string]interface{}{"value": tt.want, "case_insensitive": true}
+				if !reflect.DeepEqual(term[field], want) {
+					t.Errorf("term on %s = %v, want %v", field, term[field], want)
+				}
+			}
+		})
+	}
+}
+
+// assertSameJSON fails the test unless got and want encode the same JSON value
+func assertSameJSON(t *testing.T, got, want []byte) {
+	t.Helper()
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if err := json.Unmarshal(want, &wantValue); err != nil {
+		t.Fatalf("invalid golden JSON: %v", err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("query mismatch\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestExecuteSearchMaxResultWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		offset  int
+		limit   int
+		wantErr bool
+	}{
+		{"last full page", 9990, 10, false},
+		{"past the window", 9995, 10, true},
+		{"far past the window", 20000, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// DISTANCE without nearPoint is rejected after the window check, so
+			// searches inside the window fail before any Elasticsearch call
+			distance := model.LocationSortModeDistance
+			input := model.LocationSearchInput{Query: "patan", Offset: intPtr(tt.offset), Limit: intPtr(tt.limit), SortBy: &distance}
+
+			r := &queryResolver{&Resolver{}}
+			_, err := r.executeSearch(context.Background(), input)
+
+			var gqlErr *gqlerror.Error
+			if !errors.As(err, &gqlErr) {
+				t.Fatalf("error = %v, want a GraphQL user error", err)
+			}
+			const windowMsg = "Cannot paginate beyond 10,000 results. Use cursor-based pagination instead."
+			if gotWindow := gqlErr.Message == windowMsg; gotWindow != tt.wantErr {
+				t.Errorf("error = %q, max result window error expected: %v", gqlErr.Message, tt.wantErr)
+			}
+			if gqlErr.Extensions["code"] != "BAD_USER_INPUT" {
+				t.Errorf("code = %v, want BAD_USER_INPUT", gqlErr.Extensions["code"])
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	elasticsearch "github.com/elastic/go-elasticsearch/v8"
+
+	"search-core/graph/model"
+)
+
+// searchLogIndex is the Elasticsearch index that receives search analytics
+const searchLogIndex = "nepal_search_logs"
+
+// searchLogEntry is a single search analytics document. It intentionally
+// carries no client identifiers (IP, session, user agent).
+type searchLogEntry struct {
+	SearchID    string                 `json:"search_id"`
+	Timestamp   time.Time              `json:"timestamp"`
+	Query       string                 `json:"query"`
+	Filters     map[string]interface{} `json:"filters,omitempty"`
+	TotalHits   int                    `json:"total_hits"`
+	TookMs      int                    `json:"took_ms"`
+	TopResultID string                 `json:"top_result_id,omitempty"`
+	Variant     string                 `json:"variant"`
+}
+
+// SearchLogger indexes search analytics asynchronously so logging never
+// blocks a search response
+type SearchLogger struct {
+	esClient *elasticsearch.Client
+	entries  chan searchLogEntry
+}
+
+// NewSearchLogger creates a SearchLogger and starts its background worker
+func NewSearchLogger(esClient *elasticsearch.Client, bufferSize int) *SearchLogger {
+	l := &SearchLogger{
+		esClient: esClient,
+		entries:  make(chan searchLogEntry, bufferSize),
+	}
+	go l.run()
+	return l
+}
+
+// Log queues a search for indexing. Entries are dropped when the buffer is full.
+func (l *SearchLogger) Log(searchID string, input model.LocationSearchInput, response *model.LocationSearchResponse) {
+	entry := searchLogEntry{
+		SearchID:  searchID,
+		Timestamp: time.Now().UTC(),
+		Query:     input.Query,
+		Filters:   searchFilters(input),
+		TotalHits: response.Total,
+		TookMs:    response.Took,
+		Variant:   strings.ToLower(string(searchVariant(input))),
+	}
+	if len(response.Results) > 0 {
+		entry.TopResultID = response.Results[0].ID
+	}
+
+	select {
+	case l.entries <- entry:
+	default:
+		log.Println("WARNING: search log buffer full, dropping entry")
+	}
+}
+
+// run indexes queued entries until the channel is closed
+func (l *SearchLogger) run() {
+	for entry := range l.entries {
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(entry); err != nil {
+			log.Printf("Error encoding search log: %v", err)
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		res, err := l.esClient.Index(searchLogIndex, &buf, l.esClient.Index.WithContext(ctx))
+		cancel()
+		if err != nil {
+			log.Printf("Error indexing search log: %v", err)
+			continue
+		}
+		if res.IsError() {
+			log.Printf("Error indexing search log: %s", res.Status())
+		}
+		res.Body.Close()
+	}
+}
+
+// searchFilters collects the parent filters that were set on the input
+func searchFilters(input model.LocationSearchInput) map[string]interface{} {
+	filters := map[string]interface{}{}
+	if input.Ward != nil {
+		filters["ward"] = *input.Ward
+	}
+	if len(input.Wards) > 0 {
+		filters["wards"] = input.Wards
+	}
+	if input.Municipality != nil && *input.Municipality != "" {
+		filters["municipality"] = *input.Municipality
+	}
+	if len(input.Municipalities) > 0 {
+		filters["municipalities"] = input.Municipalities
+	}
+	if input.District != nil && *input.District != "" {
+		filters["district"] = *input.District
+	}
+	if input.Province != nil && *input.Province != "" {
+		filters["province"] = *input.Province
+	}
+	if input.ProvinceNumber != nil {
+		filters["province_number"] = *input.ProvinceNumber
+	}
+	return filters
+}
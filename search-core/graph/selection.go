@@ -0,0 +1,40 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// selectsField reports whether the resolving GraphQL field selects a field
+// called name at any depth, including through fragments. It is false outside
+// a GraphQL request.
+func selectsField(ctx context.Context, name string) bool {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Field.Field == nil {
+		return false
+	}
+	return selectionHasField(fc.Field.Selections, name)
+}
+
+// selectionHasField walks a selection set looking for a field called name
+func selectionHasField(selections ast.SelectionSet, name string) bool {
+	for _, selection := range selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			if s.Name == name || selectionHasField(s.SelectionSet, name) {
+				return true
+			}
+		case *ast.InlineFragment:
+			if selectionHasField(s.SelectionSet, name) {
+				return true
+			}
+		case *ast.FragmentSpread:
+			if s.Definition != nil && selectionHasField(s.Definition.SelectionSet, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// recentSearchesTTL is how long a session's recent searches are kept
+const recentSearchesTTL = 24 * time.Hour
+
+// errSessionStoreUnavailable is returned when no session store is configured
+var errSessionStoreUnavailable = errors.New("recent searches are not available")
+
+// SessionStore keeps the most recent unique searches per session
+type SessionStore interface {
+	SaveSearch(ctx context.Context, sessionID, query string) error
+	RecentSearches(ctx context.Context, sessionID string, limit int) ([]string, error)
+}
+
+// RedisSessionStore implements SessionStore with one Redis list per session
+type RedisSessionStore struct {
+	Client     *redis.Client
+	MaxEntries int
+}
+
+// SaveSearch moves the query to the front of the session's list and trims it to MaxEntries
+func (s *RedisSessionStore) SaveSearch(ctx context.Context, sessionID, query string) error {
+	key := recentSearchesKey(sessionID)
+
+	_, err := s.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LRem(ctx, key, 0, query)
+		pipe.LPush(ctx, key, query)
+		pipe.LTrim(ctx, key, 0, int64(s.MaxEntries-1))
+		pipe.Expire(ctx, key, recentSearchesTTL)
+		return nil
+	})
+	return err
+}
+
+// RecentSearches returns up to limit searches, newest first
+func (s *RedisSessionStore) RecentSearches(ctx context.Context, sessionID string, limit int) ([]string, error) {
+	return s.Client.LRange(ctx, recentSearchesKey(sessionID), 0, int64(limit-1)).Result()
+}
+
+func recentSearchesKey(sessionID string) string {
+	return "recent_searches:" + sessionID
+}
+
+// SaveSearch records a query in the session's recent searches
+func (r *mutationResolver) SaveSearch(ctx context.Context, sessionID string, query string) (bool, error) {
+	if r.SessionStore == nil {
+		return false, errSessionStoreUnavailable
+	}
+
+	query = strings.TrimSpace(query)
+	if sessionID == "" || query == "" {
+		return false, nil
+	}
+
+	if err := r.SessionStore.SaveSearch(ctx, sessionID, query); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RecentSearches returns the session's most recent unique searches
+func (r *queryResolver) RecentSearches(ctx context.Context, sessionID string, limit *int) ([]string, error) {
+	if r.SessionStore == nil {
+		return nil, errSessionStoreUnavailable
+	}
+
+	n := 10
+	if limit != nil && *limit > 0 {
+		n = *limit
+	}
+
+	return r.SessionStore.RecentSearches(ctx, sessionID, n)
+}
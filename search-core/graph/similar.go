@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"context"
+
+	"search-core/graph/model"
+)
+
+// similarMaxDistance bounds how far similar locations may be from the source location
+const similarMaxDistance = "25km"
+
+// SearchSimilar finds locations that are textually and geographically similar to a known location
+func (r *queryResolver) SearchSimilar(ctx context.Context, id string, limit *int) (*model.LocationSearchResponse, error) {
+	source, err := r.getLocation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, nil
+	}
+
+	query := buildSimilarQuery(source, resolveLimit(limit))
+
+	esResponse, err := r.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LocationSearchResponse{
+		Results:  convertHits(esResponse.Hits.Hits),
+		Total:    esResponse.Hits.Total.Value,
+		Took:     esResponse.Took,
+		Clusters: []*model.GeohashCluster{},
+	}, nil
+}
+
+// buildSimilarQuery creates a more_like_this query for the source document,
+// restricted to a radius around its coordinates when it has any
+func buildSimilarQuery(source *ESHit, limit int) map[string]interface{} {
+	boolQuery := map[string]interface{}{
+		"must": []map[string]interface{}{
+			{
+				"more_like_this": map[string]interface{}{
+					"fields": []string{"name", "place_type", "municipality"},
+					"like": []map[string]interface{}{
+						{"_index": locationIndex, "_id": source.ID},
+					},
+					"min_term_freq": 1,
+					"min_doc_freq":  1,
+				},
+			},
+		},
+	}
+
+	if loc := source.Source.Location; loc.Lat != 0 || loc.Lon != 0 {
+		boolQuery["filter"] = []map[string]interface{}{
+			{
+				"geo_distance": map[string]interface{}{
+					"distance": similarMaxDistance,
+					"location": map[string]interface{}{
+						"lat": loc.Lat,
+						"lon": loc.Lon,
+					},
+				},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"size": limit,
+		"query": map[string]interface{}{
+			"bool": boolQuery,
+		},
+	}
+}
@@ -0,0 +1,33 @@
+package graph
+
+// stalenessOffset is how old an OSM edit can be before its score decays
+const stalenessOffset = "730d"
+
+// stalenessDecay is the score multiplier at stalenessOffset plus the decay scale
+const stalenessDecay = 0.5
+
+// withStalenessDecay wraps a search query in a function_score that lowers the
+// score of features whose OSM data was last edited more than two years ago,
+// following a gauss curve over StalenessDecayScale. Documents without
+// osm_last_modified are unaffected.
+func (r *Resolver) withStalenessDecay(query interface{}) interface{} {
+	if r.StalenessDecayScale == "" {
+		return query
+	}
+	return map[string]interface{}{
+		"function_score": map[string]interface{}{
+			"query": query,
+			"functions": []map[string]interface{}{
+				{"gauss": map[string]interface{}{
+					"osm_last_modified": map[string]interface{}{
+						"origin": "now",
+						"offset": stalenessOffset,
+						"scale":  r.StalenessDecayScale,
+						"decay":  stalenessDecay,
+					},
+				}},
+			},
+			"boost_mode": "multiply",
+		},
+	}
+}
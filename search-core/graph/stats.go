@@ -0,0 +1,76 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"search-core/graph/model"
+)
+
+// GetMunicipalityStats aggregates ward count, location types and centroid for a municipality
+func (r *queryResolver) GetMunicipalityStats(ctx context.Context, municipality string) (*model.MunicipalityStats, error) {
+	query := map[string]interface{}{
+		"size": 0,
+		"query": map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":  municipality,
+				"fields": []string{"municipality.keyword", "municipality_ne.keyword"},
+				"type":   "best_fields",
+			},
+		},
+		"aggs": map[string]interface{}{
+			"by_type": map[string]interface{}{
+				"terms": map[string]interface{}{"field": "entity_type"},
+			},
+			"max_ward": map[string]interface{}{
+				"max": map[string]interface{}{"field": "ward"},
+			},
+			"centroid": map[string]interface{}{
+				"geo_centroid": map[string]interface{}{"field": "location"},
+			},
+		},
+	}
+
+	esResponse, err := r.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var aggs struct {
+		ByType struct {
+			Buckets []struct {
+				Key      string `json:"key"`
+				DocCount int    `json:"doc_count"`
+			} `json:"buckets"`
+		} `json:"by_type"`
+		MaxWard struct {
+			Value *float64 `json:"value"`
+		} `json:"max_ward"`
+		Centroid struct {
+			Location *ESGeoPoint `json:"location"`
+		} `json:"centroid"`
+	}
+	if err := json.Unmarshal(esResponse.Aggregations, &aggs); err != nil {
+		return nil, fmt.Errorf("error parsing aggregations: %w", err)
+	}
+
+	stats := &model.MunicipalityStats{
+		TotalLocations:  esResponse.Hits.Total.Value,
+		LocationsByType: make([]*model.FacetBucket, 0, len(aggs.ByType.Buckets)),
+	}
+	if aggs.MaxWard.Value != nil {
+		stats.TotalWards = int(*aggs.MaxWard.Value)
+	}
+	if aggs.Centroid.Location != nil {
+		stats.Centroid = convertGeoPoint(*aggs.Centroid.Location)
+	}
+	for _, bucket := range aggs.ByType.Buckets {
+		stats.LocationsByType = append(stats.LocationsByType, &model.FacetBucket{
+			Key:   bucket.Key,
+			Count: bucket.DocCount,
+		})
+	}
+
+	return stats, nil
+}
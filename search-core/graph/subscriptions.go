@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"search-core/graph/model"
+)
+
+// errSubscriptionsUnavailable is returned when no Redis event bus is configured
+var errSubscriptionsUnavailable = errors.New("location update subscriptions are not available")
+
+// locationUpdatesChannel is the Redis Pub/Sub channel the syncer publishes a
+// municipality's indexed locations to. Must match the syncer's channel naming.
+func locationUpdatesChannel(municipality string) string {
+	return "nepal_locations:" + strings.ToLower(strings.TrimSpace(municipality))
+}
+
+// LocationUpdated streams locations in a municipality as the syncer indexes them
+func (r *subscriptionResolver) LocationUpdated(ctx context.Context, municipality string) (<-chan *model.Location, error) {
+	if r.PubSub == nil {
+		return nil, errSubscriptionsUnavailable
+	}
+	if strings.TrimSpace(municipality) == "" {
+		return nil, userError("municipality must not be empty")
+	}
+
+	sub := r.PubSub.Subscribe(ctx, locationUpdatesChannel(municipality))
+	// Wait for the subscription to be confirmed so connection errors are returned
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, fmt.Errorf("error subscribing to location updates: %w", err)
+	}
+
+	events := make(chan *model.Location)
+	go func() {
+		defer close(events)
+		defer sub.Close()
+
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				location, err := decodeLocationEvent(msg)
+				if err != nil {
+					log.Printf("WARNING: dropping location update on %s: %v", msg.Channel, err)
+					continue
+				}
+				select {
+				case events <- location:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// decodeLocationEvent parses a syncer message, an {"_id", "_source"} document
+func decodeLocationEvent(msg *redis.Message) (*model.Location, error) {
+	var hit ESHit
+	if err := json.Unmarshal([]byte(msg.Payload), &hit); err != nil {
+		return nil, fmt.Errorf("error parsing message: %w", err)
+	}
+	if hit.ID == "" {
+		return nil, errors.New("message has no _id")
+	}
+	return convertToLocation(hit), nil
+}
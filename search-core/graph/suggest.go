@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxCorrections caps the number of suggestions returned by SuggestCorrection
+const maxCorrections = 5
+
+// correctableFields are the fields SuggestCorrection accepts
+var correctableFields = map[string]bool{
+	"municipality": true,
+	"district":     true,
+	"province":     true,
+}
+
+// SuggestCorrection proposes spelling corrections for a single field value
+// using the Elasticsearch term suggester
+func (r *queryResolver) SuggestCorrection(ctx context.Context, text string, field string) ([]string, error) {
+	if !correctableFields[field] {
+		return nil, userError("field must be one of: municipality, district, province")
+	}
+	if strings.TrimSpace(text) == "" {
+		return []string{}, nil
+	}
+
+	query := map[string]interface{}{
+		"size": 0,
+		"suggest": map[string]interface{}{
+			"correction": map[string]interface{}{
+				"text": text,
+				"term": map[string]interface{}{
+					"field":        field,
+					"suggest_mode": "popular",
+					"sort":         "frequency",
+					"size":         maxCorrections,
+				},
+			},
+		},
+	}
+
+	esResponse, err := r.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var suggest struct {
+		Correction []struct {
+			Text    string `json:"text"`
+			Offset  int    `json:"offset"`
+			Length  int    `json:"length"`
+			Options []struct {
+				Text string `json:"text"`
+				Freq int    `json:"freq"`
+			} `json:"options"`
+		} `json:"correction"`
+	}
+	if err := json.Unmarshal(esResponse.Suggest, &suggest); err != nil {
+		return nil, fmt.Errorf("error parsing suggestions: %w", err)
+	}
+
+	// Each option replaces its misspelled term in the original text. Offsets
+	// are in characters, so slice runes rather than bytes.
+	runes := []rune(text)
+	type candidate struct {
+		text string
+		freq int
+	}
+	var candidates []candidate
+	seen := map[string]bool{}
+	for _, entry := range suggest.Correction {
+		if entry.Offset < 0 || entry.Offset+entry.Length > len(runes) {
+			continue
+		}
+		for _, option := range entry.Options {
+			corrected := string(runes[:entry.Offset]) + option.Text + string(runes[entry.Offset+entry.Length:])
+			if seen[corrected] {
+				continue
+			}
+			seen[corrected] = true
+			candidates = append(candidates, candidate{text: corrected, freq: option.Freq})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].freq > candidates[j].freq
+	})
+
+	corrections := make([]string, 0, maxCorrections)
+	for _, c := range candidates {
+		if len(corrections) == maxCorrections {
+			break
+		}
+		corrections = append(corrections, c.text)
+	}
+	return corrections, nil
+}
@@ -0,0 +1,38 @@
+package graph
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log"
+	"strings"
+)
+
+// synonymsJSON maps canonical Nepal place names to their known aliases
+// (old province numbers, historical and colloquial names)
+//
+//go:embed synonyms.json
+var synonymsJSON []byte
+
+// synonymIndex maps a lowercased alias to its canonical name
+var synonymIndex = loadSynonyms(synonymsJSON)
+
+func loadSynonyms(data []byte) map[string]string {
+	var canonical map[string][]string
+	if err := json.Unmarshal(data, &canonical); err != nil {
+		log.Fatalf("Error parsing synonyms.json: %v", err)
+	}
+
+	index := make(map[string]string)
+	for name, aliases := range canonical {
+		for _, alias := range aliases {
+			index[strings.ToLower(alias)] = name
+		}
+	}
+	return index
+}
+
+// lookupSynonym returns the canonical name when q is a known alias
+func lookupSynonym(q string) (string, bool) {
+	name, ok := synonymIndex[strings.ToLower(strings.TrimSpace(q))]
+	return name, ok
+}
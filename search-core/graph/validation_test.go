@@ -0,0 +1,172 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+
+	"search-core/graph/model"
+)
+
+func TestPerformValidation(t *testing.T) {
+	patan := &model.Location{
+		Name:         "Patan Durbar Square",
+		Ward:         intPtr(16),
+		Municipality: strPtr("Lalitpur"),
+		District:     strPtr("Lalitpur"),
+		Province:     strPtr("Bagmati"),
+	}
+	orphan := &model.Location{Name: "Orphaned Place"}
+
+	// mismatch is the expected mismatch of one field as field/expected/actual
+	type mismatch [3]string
+
+	tests := []struct {
+		name       string
+		input      model.LocationSearchInput
+		results    []*model.Location
+		wantNil    bool
+		wantValid  bool
+		mismatches []mismatch
+		message    string
+	}{
+		{
+			name:    "no filters",
+			input:   model.LocationSearchInput{Query: "patan"},
+			results: []*model.Location{patan},
+			wantNil: true,
+		},
+		{
+			name:    "empty filters count as unset",
+			input:   model.LocationSearchInput{Query: "patan", Municipality: strPtr(""), District: strPtr("")},
+			results: []*model.Location{patan},
+			wantNil: true,
+		},
+		{
+			name: "all filters match",
+			input: model.LocationSearchInput{
+				Query: "patan", Ward: intPtr(16), Municipality: strPtr("lalitpur"),
+				District: strPtr(" Lalitpur "), Province: strPtr("BAGMATI"),
+			},
+			results:   []*model.Location{patan},
+			wantValid: true,
+			message:   "All parent locations match",
+		},
+		{
+			name:       "ward mismatch",
+			input:      model.LocationSearchInput{Query: "patan", Ward: intPtr(5)},
+			results:    []*model.Location{patan},
+			mismatches: []mismatch{{"ward", "5", "16"}},
+			message:    "Found 1 mismatch(es) in parent location hierarchy",
+		},
+		{
+			name:       "municipality mismatch",
+			input:      model.LocationSearchInput{Query: "patan", Municipality: strPtr("Kathmandu")},
+			results:    []*model.Location{patan},
+			mismatches: []mismatch{{"municipality", "Kathmandu", "Lalitpur"}},
+			message:    "Found 1 mismatch(es) in parent location hierarchy",
+		},
+		{
+			name:       "district mismatch",
+			input:      model.LocationSearchInput{Query: "patan", District: strPtr("Bhaktapur")},
+			results:    []*model.Location{patan},
+			mismatches: []mismatch{{"district", "Bhaktapur", "Lalitpur"}},
+			message:    "Found 1 mismatch(es) in parent location hierarchy",
+		},
+		{
+			name:       "province mismatch",
+			input:      model.LocationSearchInput{Query: "patan", Province: strPtr("Gandaki")},
+			results:    []*model.Location{patan},
+			mismatches: []mismatch{{"province", "Gandaki", "Bagmati"}},
+			message:    "Found 1 mismatch(es) in parent location hierarchy",
+		},
+		{
+			name: "multiple mismatches",
+			input: model.LocationSearchInput{
+				Query: "patan", Ward: intPtr(1), Municipality: strPtr("Pokhara"),
+				District: strPtr("Kaski"), Province: strPtr("Gandaki"),
+			},
+			results: []*model.Location{patan},
+			mismatches: []mismatch{
+				{"ward", "1", "16"},
+				{"municipality", "Pokhara", "Lalitpur"},
+				{"district", "Kaski", "Lalitpur"},
+				{"province", "Gandaki", "Bagmati"},
+			},
+			message: "Found 4 mismatch(es) in parent location hierarchy",
+		},
+		{
+			name: "missing parents on the result",
+			input: model.LocationSearchInput{
+				Query: "orphaned", Ward: intPtr(3), Municipality: strPtr("Lalitpur"),
+				District: strPtr("Lalitpur"), Province: strPtr("Bagmati"),
+			},
+			results: []*model.Location{orphan},
+			mismatches: []mismatch{
+				{"ward", "3", "null"},
+				{"municipality", "Lalitpur", "null"},
+				{"district", "Lalitpur", "null"},
+				{"province", "Bagmati", "null"},
+			},
+			message: "Found 4 mismatch(es) in parent location hierarchy",
+		},
+		{
+			name:       "unknown parent name",
+			input:      model.LocationSearchInput{Query: "patan", District: strPtr("Atlantis")},
+			results:    []*model.Location{patan},
+			mismatches: []mismatch{{"district", "Atlantis", "Lalitpur"}},
+			message:    "Found 1 mismatch(es) in parent location hierarchy",
+		},
+		{
+			name:    "empty results with filters",
+			input:   model.LocationSearchInput{Query: "patan", District: strPtr("Lalitpur")},
+			results: nil,
+			message: "No results found matching the provided criteria",
+		},
+		{
+			name:      "only the top result is validated",
+			input:     model.LocationSearchInput{Query: "patan", District: strPtr("Lalitpur")},
+			results:   []*model.Location{patan, orphan},
+			wantValid: true,
+			message:   "All parent locations match",
+		},
+		{
+			name: "municipality merged into a list is not validated",
+			input: model.LocationSearchInput{
+				Query: "patan", Municipality: strPtr("Kathmandu"), Municipalities: []string{"Lalitpur"},
+			},
+			results:   []*model.Location{patan},
+			wantValid: true,
+			message:   "All parent locations match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := performValidation(tt.input, tt.results)
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("performValidation = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("performValidation = nil, want a result")
+			}
+
+			if got.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, want %v", got.Valid, tt.wantValid)
+			}
+			if got.Message == nil || *got.Message != tt.message {
+				t.Errorf("Message = %v, want %q", strVal(got.Message), tt.message)
+			}
+
+			var mismatches []mismatch
+			for _, m := range got.Mismatches {
+				mismatches = append(mismatches, mismatch{m.Field, m.Expected, strVal(m.Actual)})
+			}
+			if !reflect.DeepEqual(mismatches, tt.mismatches) {
+				t.Errorf("Mismatches = %v, want %v", mismatches, tt.mismatches)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"search-core/graph/model"
+)
+
+// fuzzinessPattern matches the Elasticsearch fuzziness values 0-2, AUTO and AUTO:low,high
+var fuzzinessPattern = regexp.MustCompile(`^([0-2]|AUTO(:\d+,\d+)?)$`)
+
+// RankingVariant is the ranking configuration of one A/B test search variant.
+// Unset settings keep the service defaults.
+type RankingVariant struct {
+	// FieldBoosts overrides text field boosts in place of ES_FIELD_BOOSTS
+	FieldBoosts map[string]float64
+	// Fuzziness replaces the AUTO fuzziness of text matching
+	Fuzziness string
+	// BoostScoreFirst orders relevance results by boost_score before _score
+	BoostScoreFirst bool
+}
+
+// rankingVariantSpec is a variant as written in the SEARCH_VARIANTS file
+type rankingVariantSpec struct {
+	FieldBoosts     string `json:"fieldBoosts"`
+	Fuzziness       string `json:"fuzziness"`
+	BoostScoreFirst bool   `json:"boostScoreFirst"`
+}
+
+// ParseSearchVariants parses a SEARCH_VARIANTS JSON object keyed by variant,
+// such as {"treatment": {"fieldBoosts": "name^5,name_ne^5", "fuzziness": "1"}}.
+// Variants left out of the object run with the defaults.
+func ParseSearchVariants(data []byte) (map[model.SearchVariant]*RankingVariant, error) {
+	var specs map[string]rankingVariantSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return nil, fmt.Errorf("error parsing search variants: %w", err)
+	}
+
+	variants := make(map[model.SearchVariant]*RankingVariant, len(specs))
+	for name, spec := range specs {
+		key := model.SearchVariant(strings.ToUpper(name))
+		if !key.IsValid() {
+			return nil, fmt.Errorf("unknown search variant %q", name)
+		}
+
+		variant := &RankingVariant{BoostScoreFirst: spec.BoostScoreFirst}
+		if spec.FieldBoosts != "" {
+			boosts, err := ParseFieldBoosts(spec.FieldBoosts)
+			if err != nil {
+				return nil, fmt.Errorf("variant %s: %w", name, err)
+			}
+			variant.FieldBoosts = boosts
+		}
+		if spec.Fuzziness != "" {
+			if !fuzzinessPattern.MatchString(spec.Fuzziness) {
+				return nil, fmt.Errorf("variant %s: invalid fuzziness %q", name, spec.Fuzziness)
+			}
+			variant.Fuzziness = spec.Fuzziness
+		}
+		variants[key] = variant
+	}
+	return variants, nil
+}
+
+// searchVariant returns the variant a search runs with, CONTROL by default
+func searchVariant(input model.LocationSearchInput) model.SearchVariant {
+	if input.SearchVariant == nil {
+		return model.SearchVariantControl
+	}
+	return *input.SearchVariant
+}
+
+// rankingVariant returns the configuration of the search's variant, or nil
+// when the variant runs with the defaults
+func (r *Resolver) rankingVariant(input model.LocationSearchInput) *RankingVariant {
+	return r.SearchVariants[searchVariant(input)]
+}
+
+// fieldBoosts returns the text field boost overrides for a variant
+func (r *Resolver) fieldBoosts(variant *RankingVariant) map[string]float64 {
+	if variant != nil && variant.FieldBoosts != nil {
+		return variant.FieldBoosts
+	}
+	return r.FieldBoosts
+}
+
+// fuzziness returns the text matching fuzziness for a variant
+func fuzziness(variant *RankingVariant) string {
+	if variant != nil && variant.Fuzziness != "" {
+		return variant.Fuzziness
+	}
+	return "AUTO"
+}
@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"search-core/graph/model"
+)
+
+// variantHits answers a search with the order Elasticsearch would produce for
+// its sort: the popular city by boost_score first, otherwise the closer text match
+func variantHits(req esRequest) (int, string) {
+	if !strings.HasSuffix(req.path, "/_search") {
+		return http.StatusOK, `{}`
+	}
+	textMatch := `{"_id":"node_1","_score":10,"_source":{"osm_id":1,"name":"Bharatpur Chowk","boost_score":1},"sort":[10,1,"node_1"]}`
+	city := `{"_id":"relation_2","_score":8,"_source":{"osm_id":-2,"name":"Bharatpur","boost_score":1.1},"sort":[1.1,8,"relation_2"]}`
+	hits := textMatch + "," + city
+	if strings.Index(req.body, `"boost_score"`) < strings.Index(req.body, `"_score"`) {
+		hits = city + "," + textMatch
+	}
+	return http.StatusOK, `{"hits":{"total":{"value":2},"max_score":10,"hits":[` + hits + `]}}`
+}
+
+func TestSearchVariantsOrderDifferently(t *testing.T) {
+	fake := &fakeES{respond: variantHits}
+	r := newFakeESResolver(t, fake)
+	r.Ranker = BoostScoreRanker{}
+	r.SearchVariants = map[model.SearchVariant]*RankingVariant{
+		model.SearchVariantTreatment: {BoostScoreFirst: true},
+	}
+
+	order := func(variant model.SearchVariant) []string {
+		t.Helper()
+		response, err := (&queryResolver{r}).SearchLocation(t.Context(), model.LocationSearchInput{
+			Query:         "bharatpur",
+			SearchVariant: &variant,
+		})
+		if err != nil {
+			t.Fatalf("SearchLocation(%s): %v", variant, err)
+		}
+		var names []string
+		for _, loc := range response.Results {
+			names = append(names, loc.Name)
+		}
+		return names
+	}
+
+	control := order(model.SearchVariantControl)
+	treatment := order(model.SearchVariantTreatment)
+
+	// The ranker gives the text match 10 and the city 8*1.1, so control keeps
+	// the text match first; treatment must not rerank Elasticsearch's order
+	if want := []string{"Bharatpur Chowk", "Bharatpur"}; strings.Join(control, "|") != strings.Join(want, "|") {
+		t.Errorf("control order = %v, want %v", control, want)
+	}
+	if want := []string{"Bharatpur", "Bharatpur Chowk"}; strings.Join(treatment, "|") != strings.Join(want, "|") {
+		t.Errorf("treatment order = %v, want %v", treatment, want)
+	}
+}
@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// GetWardBoundary returns a ward's official boundary polygon from the
+// boundaries index as a GeoJSON geometry string. Many wards have no official
+// digital boundary, so a missing one yields null with a "warnings" response
+// extension instead of an error.
+func (r *queryResolver) GetWardBoundary(ctx context.Context, municipality string, ward int) (*string, error) {
+	municipality = strings.TrimSpace(municipality)
+	if municipality == "" {
+		return nil, userError("municipality must not be empty")
+	}
+	if ward < 1 {
+		return nil, userError("ward must be at least 1")
+	}
+
+	query := map[string]interface{}{
+		"size":    1,
+		"_source": []string{"boundary"},
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []map[string]interface{}{
+					{"term": map[string]interface{}{"ward": ward}},
+					{"match": map[string]interface{}{
+						"municipality": map[string]interface{}{"query": municipality, "operator": "and"},
+					}},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+		return nil, fmt.Errorf("error encoding query: %w", err)
+	}
+
+	// The boundaries index only exists once an official GeoJSON import has run
+	res, err := r.ESClient.Search(
+		r.ESClient.Search.WithContext(ctx),
+		r.ESClient.Search.WithIndex(boundariesIndex),
+		r.ESClient.Search.WithBody(&buf),
+		r.ESClient.Search.WithIgnoreUnavailable(true),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error executing search: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("elasticsearch error: %s - %s", res.Status(), string(body))
+	}
+
+	var esResponse struct {
+		Hits struct {
+			Hits []struct {
+				Source struct {
+					Boundary json.RawMessage `json:"boundary"`
+				} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&esResponse); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	if len(esResponse.Hits.Hits) == 0 || len(esResponse.Hits.Hits[0].Source.Boundary) == 0 {
+		graphql.RegisterExtension(ctx, "warnings", []string{
+			fmt.Sprintf("No official boundary is indexed for %s ward %d", municipality, ward),
+		})
+		return nil, nil
+	}
+	return strPtr(string(esResponse.Hits.Hits[0].Source.Boundary)), nil
+}
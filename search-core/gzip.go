@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+)
+
+// gzipMiddleware compresses responses of at least minSize bytes for clients that
+// send Accept-Encoding: gzip. Smaller responses are sent uncompressed.
+func gzipMiddleware(minSize int, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		// WebSocket upgrades (subscriptions) need the connection unwrapped to hijack it
+		if !acceptsGzip(r) || strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w, minSize: minSize, status: http.StatusOK}
+		defer gw.Close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding allows gzip
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if strings.TrimSpace(name) != "gzip" {
+			continue
+		}
+		return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the response until it reaches minSize, then
+// switches to gzip. Responses that finish below minSize are written as-is.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minSize     int
+	status      int
+	wroteHeader bool
+	buf         bytes.Buffer
+	gz          *gzip.Writer
+}
+
+// WriteHeader records the status; it is sent once the encoding is decided
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+}
+
+// Write buffers small responses and compresses once minSize is reached
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	if w.gz != nil {
+		return w.gz.Write(p)
+	}
+
+	w.buf.Write(p)
+	if w.buf.Len() < w.minSize || w.Header().Get("Content-Encoding") != "" {
+		return len(p), nil
+	}
+
+	h := w.Header()
+	h.Set("Content-Encoding", "gzip")
+	h.Del("Content-Length")
+	w.ResponseWriter.WriteHeader(w.status)
+
+	w.gz = gzip.NewWriter(w.ResponseWriter)
+	if _, err := w.gz.Write(w.buf.Bytes()); err != nil {
+		return 0, err
+	}
+	w.buf.Reset()
+	return len(p), nil
+}
+
+// Close flushes the gzip stream, or writes the buffered response uncompressed
+func (w *gzipResponseWriter) Close() error {
+	if w.gz != nil {
+		return w.gz.Close()
+	}
+	if w.wroteHeader {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	return err
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"search-core/graph/model"
+)
+
+// searchResponseBody returns a GraphQL searchLocation response with n results
+// carrying the full set of commonly requested fields
+func searchResponseBody(n int) []byte {
+	str := func(s string) *string { return &s }
+	num := func(i int) *int { return &i }
+
+	results := make([]*model.Location, n)
+	for i := range results {
+		results[i] = &model.Location{
+			ID:             fmt.Sprintf("place_%d", 1000+i),
+			EntityType:     "place",
+			Name:           fmt.Sprintf("Patan Tole %d", i),
+			NameNe:         str("पाटन टोल"),
+			NameEn:         str(fmt.Sprintf("Patan Tole %d", i)),
+			PlaceType:      str("neighbourhood"),
+			Location:       &model.GeoPoint{Lat: 27.6766 + float64(i)/1000, Lon: 85.3188 + float64(i)/1000},
+			Ward:           num(i%32 + 1),
+			Municipality:   str("Lalitpur"),
+			MunicipalityNe: str("ललितपुर"),
+			District:       str("Lalitpur"),
+			DistrictNe:     str("ललितपुर"),
+			Province:       str("Bagmati"),
+			ProvinceNe:     str("बागमती"),
+			ProvinceNumber: num(3),
+			Country:        "Nepal",
+			LastUpdated:    str("2026-10-01T05:45:00Z"),
+			OsmID:          str(fmt.Sprintf("%d", 2468013579+i)),
+			Score:          12.5 - float64(i)/10,
+		}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"data": map[string]interface{}{
+			"searchLocation": model.LocationSearchResponse{Results: results, Total: n, Took: 12},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return body
+}
+
+func TestGzipMiddlewareThreshold(t *testing.T) {
+	tests := []struct {
+		name         string
+		size         int
+		acceptGzip   bool
+		wantEncoding string
+	}{
+		{"large response", 4096, true, "gzip"},
+		{"below threshold", 512, true, ""},
+		{"client without gzip", 4096, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := make([]byte, tt.size)
+			for i := range payload {
+				payload[i] = 'a' + byte(i%26)
+			}
+			handler := gzipMiddleware(1024, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write(payload)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/query", nil)
+			if tt.acceptGzip {
+				req.Header.Set("Accept-Encoding", "gzip, deflate")
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			var body io.Reader = rec.Body
+			if tt.wantEncoding == "gzip" {
+				gz, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatalf("gzip.NewReader: %v", err)
+				}
+				body = gz
+			}
+			got, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatalf("reading body: %v", err)
+			}
+			if string(got) != string(payload) {
+				t.Errorf("body differs from the handler's response (%d bytes, want %d)", len(got), len(payload))
+			}
+		})
+	}
+}
+
+// BenchmarkGzipSearchResponse compresses a typical 50-result search response
+// and reports its compression ratio: go test -bench GzipSearchResponse
+func BenchmarkGzipSearchResponse(b *testing.B) {
+	body := searchResponseBody(50)
+	handler := gzipMiddleware(1024, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+
+	var compressed int
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	for b.Loop() {
+		req := httptest.NewRequest(http.MethodPost, "/query", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		compressed = rec.Body.Len()
+	}
+
+	b.ReportMetric(float64(len(body)), "raw_bytes")
+	b.ReportMetric(float64(compressed), "gzip_bytes")
+	b.ReportMetric(float64(len(body))/float64(compressed), "ratio")
+}
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"net/http"
+
+	"search-core/graph"
+)
+
+// responseHeadersMiddleware lets resolvers set response headers such as X-Search-ID
+func responseHeadersMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(graph.WithResponseHeaders(r.Context(), w.Header())))
+	})
+}
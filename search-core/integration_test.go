@@ -0,0 +1,190 @@
+//go:build integration
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	elasticsearch "github.com/elastic/go-elasticsearch/v8"
+	tcelasticsearch "github.com/testcontainers/testcontainers-go/modules/elasticsearch"
+
+	"search-core/graph"
+)
+
+// Integration tests start a real Elasticsearch in Docker:
+//
+//	go test -tags integration -run Integration .
+
+const integrationESImage = "docker.elastic.co/elasticsearch/elasticsearch:8.15.3"
+
+// integrationFixtures are location documents shaped like the syncer's output
+var integrationFixtures = []map[string]interface{}{
+	{
+		"id": "admin_1", "entity_type": "admin_boundary", "admin_level": 6,
+		"name": "Kathmandu", "name_ne": "काठमाडौं", "name_en": "Kathmandu",
+		"district": "Kathmandu", "district_ne": "काठमाडौं", "province": "Bagmati", "province_ne": "बागमती",
+		"province_number": 3, "country": "Nepal", "location": map[string]float64{"lat": 27.7172, "lon": 85.3240},
+		"boost_score": 1.8, "search_text": "Kathmandu काठमाडौं",
+	},
+	{
+		"id": "place_2", "entity_type": "place", "place_type": "city",
+		"name": "Lalitpur", "name_ne": "ललितपुर", "name_en": "Lalitpur",
+		"municipality": "Lalitpur", "district": "Lalitpur", "province": "Bagmati",
+		"province_number": 3, "country": "Nepal", "location": map[string]float64{"lat": 27.6588, "lon": 85.3247},
+		"boost_score": 2.0, "search_text": "Lalitpur ललितपुर",
+	},
+	{
+		"id": "place_3", "entity_type": "place", "place_type": "city",
+		"name": "Pokhara", "name_ne": "पोखरा", "name_en": "Pokhara",
+		"municipality": "Pokhara", "district": "Kaski", "province": "Gandaki",
+		"province_number": 4, "country": "Nepal", "location": map[string]float64{"lat": 28.2096, "lon": 83.9856},
+		"boost_score": 2.0, "search_text": "Pokhara पोखरा",
+	},
+}
+
+func TestIntegrationSearchLocation(t *testing.T) {
+	if testing.Short() {
+		t.Skip("starts an Elasticsearch container")
+	}
+	ctx := context.Background()
+
+	container, err := tcelasticsearch.Run(ctx, integrationESImage)
+	if err != nil {
+		t.Fatalf("starting Elasticsearch: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := container.Terminate(context.Background()); err != nil {
+			t.Logf("terminating Elasticsearch: %v", err)
+		}
+	})
+
+	esClient, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: []string{container.Settings.Address},
+		Username:  container.Settings.Username,
+		Password:  container.Settings.Password,
+		CACert:    container.Settings.CACert,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if err := waitForElasticsearch(esClient, time.Minute); err != nil {
+		t.Fatalf("Elasticsearch not ready: %v", err)
+	}
+
+	createLocationsIndex(t, esClient)
+	seedLocations(t, esClient, integrationFixtures)
+
+	resolver := &graph.Resolver{
+		ESClient:            esClient,
+		DefaultCountry:      "NP",
+		MinQueryLength:      2,
+		MaxQueryLength:      500,
+		StalenessDecayScale: "365d",
+		SearchMaxDuration:   5 * time.Second,
+	}
+	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{
+		Resolvers:  resolver,
+		Directives: graph.DirectiveRoot{Admin: graph.AdminDirective},
+	}))
+
+	var response struct {
+		Data struct {
+			SearchLocation struct {
+				Total   int
+				Results []struct {
+					Name     string
+					District *string
+				}
+			}
+		}
+		Errors []struct{ Message string }
+	}
+	graphqlRequest(t, srv, `{ searchLocation(input: {query: "Kathmandu"}) { total results { name district } } }`, &response)
+
+	if len(response.Errors) > 0 {
+		t.Fatalf("GraphQL errors: %+v", response.Errors)
+	}
+	results := response.Data.SearchLocation.Results
+	if len(results) == 0 {
+		t.Fatal("searchLocation returned no results")
+	}
+	if top := results[0]; top.Name != "Kathmandu" || top.District == nil || *top.District != "Kathmandu" {
+		t.Errorf("top result = %s in district %v, want Kathmandu in district Kathmandu", top.Name, top.District)
+	}
+}
+
+// createLocationsIndex creates nepal_locations with the production mapping,
+// without replicas on the single-node cluster
+func createLocationsIndex(t *testing.T, esClient *elasticsearch.Client) {
+	t.Helper()
+	data, err := os.ReadFile("../elasticsearch/mappings/nepal_locations.json")
+	if err != nil {
+		t.Fatalf("reading mapping: %v", err)
+	}
+	var mapping map[string]interface{}
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		t.Fatalf("parsing mapping: %v", err)
+	}
+	mapping["settings"].(map[string]interface{})["number_of_replicas"] = 0
+	body, _ := json.Marshal(mapping)
+
+	res, err := esClient.Indices.Create("nepal_locations", esClient.Indices.Create.WithBody(bytes.NewReader(body)))
+	if err != nil {
+		t.Fatalf("creating index: %v", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		t.Fatalf("creating index: %s", res.String())
+	}
+}
+
+// seedLocations bulk-indexes docs and refreshes the index so they are searchable
+func seedLocations(t *testing.T, esClient *elasticsearch.Client, docs []map[string]interface{}) {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, doc := range docs {
+		meta, _ := json.Marshal(map[string]interface{}{"index": map[string]interface{}{"_id": doc["id"]}})
+		source, _ := json.Marshal(doc)
+		buf.Write(meta)
+		buf.WriteByte('\n')
+		buf.Write(source)
+		buf.WriteByte('\n')
+	}
+
+	res, err := esClient.Bulk(&buf, esClient.Bulk.WithIndex("nepal_locations"), esClient.Bulk.WithRefresh("true"))
+	if err != nil {
+		t.Fatalf("seeding locations: %v", err)
+	}
+	defer res.Body.Close()
+	var result struct {
+		Errors bool `json:"errors"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil || res.IsError() || result.Errors {
+		t.Fatalf("seeding locations failed: %s", res.Status())
+	}
+}
+
+// graphqlRequest POSTs query to the GraphQL handler and decodes the response into out
+func graphqlRequest(t *testing.T, srv http.Handler, query string, out interface{}) {
+	t.Helper()
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GraphQL status %d: %s", rec.Code, rec.Body.String())
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+		t.Fatalf("decoding GraphQL response: %v\n%s", err, rec.Body.String())
+	}
+}
@@ -0,0 +1,66 @@
+// Package normalize canonicalizes Nepali text so differently typed queries match alike
+package normalize
+
+import (
+	"strings"
+	"unicode"
+)
+
+// devanagariDigits maps Devanagari numerals (०-९) to ASCII digits
+var devanagariDigits = strings.NewReplacer(
+	"०", "0", "१", "1", "२", "2", "३", "3", "४", "4",
+	"५", "5", "६", "6", "७", "7", "८", "8", "९", "9",
+)
+
+// DevanagariNumerals replaces Devanagari numerals in s with ASCII
+// digits, so "वडा ५" parses like "वडा 5"
+func DevanagariNumerals(s string) string {
+	return devanagariDigits.Replace(s)
+}
+
+// nepaliPhonemes maps phonetically similar Devanagari characters to one
+// canonical form. Voice-to-text and casual typing often confuse aspirated
+// and voiced pairs (क/ग), the three sibilants, and long and short vowels.
+// Keep in sync with NEPALI_PHONEMES in scripts/sync_to_elasticsearch.py,
+// which applies the same mapping to name_ne_phonetic at index time.
+var nepaliPhonemes = map[rune]rune{
+	// Velars
+	'ख': 'क', 'ग': 'क', 'घ': 'क',
+	// Palatals
+	'छ': 'च', 'ज': 'च', 'झ': 'च',
+	// Retroflexes
+	'ठ': 'ट', 'ड': 'ट', 'ढ': 'ट',
+	// Dentals
+	'थ': 'त', 'द': 'त', 'ध': 'त',
+	// Labials (व is commonly written and spoken as ब)
+	'फ': 'प', 'ब': 'प', 'भ': 'प', 'व': 'प',
+	// Nasals
+	'ङ': 'न', 'ञ': 'न', 'ण': 'न',
+	// Sibilants
+	'श': 'स', 'ष': 'स',
+	// Long and short vowels and vowel signs
+	'ई': 'इ', 'ऊ': 'उ', 'ी': 'ि', 'ू': 'ु',
+	// Nasalization
+	'ँ': 'ं',
+}
+
+// NepaliPhonemes maps phonetically similar Devanagari characters in
+// s to canonical forms, so "गोरखा" and "कोरका" normalize alike
+func NepaliPhonemes(s string) string {
+	return strings.Map(func(r rune) rune {
+		if canonical, ok := nepaliPhonemes[r]; ok {
+			return canonical
+		}
+		return r
+	}, s)
+}
+
+// HasDevanagari reports whether s contains any Devanagari characters
+func HasDevanagari(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Devanagari, r) {
+			return true
+		}
+	}
+	return false
+}
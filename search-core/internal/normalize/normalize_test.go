@@ -0,0 +1,29 @@
+package normalize
+
+import "testing"
+
+func TestDevanagariNumerals(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"०", "0"},
+		{"वडा १", "वडा 1"},
+		{"वडा नं. २", "वडा नं. 2"},
+		{"Ward ३", "Ward 3"},
+		{"बानेश्वर-४, काठमाडौं", "बानेश्वर-4, काठमाडौं"},
+		{"५६", "56"},
+		{"१०७८९", "10789"},
+		{"Thamel ward 2६", "Thamel ward 26"},
+		{"९ नम्बर", "9 नम्बर"},
+		{"Patan 15", "Patan 15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := DevanagariNumerals(tt.in); got != tt.want {
+				t.Errorf("DevanagariNumerals(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
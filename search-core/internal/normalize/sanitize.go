@@ -0,0 +1,32 @@
+package normalize
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// SanitizeQuery cleans raw user input before any other processing: it
+// normalizes to Unicode NFC, strips control and format characters (null
+// bytes, direction overrides, zero-width characters) and collapses runs of
+// whitespace into single spaces.
+func SanitizeQuery(q string) string {
+	q = norm.NFC.String(q)
+	q = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r) && !isDevanagariJoiner(r):
+			return -1
+		}
+		return r
+	}, q)
+	return strings.Join(strings.Fields(q), " ")
+}
+
+// isDevanagariJoiner reports whether r is a zero-width joiner or non-joiner,
+// which select conjunct forms in Devanagari and must be kept
+func isDevanagariJoiner(r rune) bool {
+	return r == '\u200c' || r == '\u200d'
+}
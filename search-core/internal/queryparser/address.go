@@ -0,0 +1,121 @@
+package queryparser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"search-core/internal/normalize"
+)
+
+// AddressRole is the admin division an address component names
+type AddressRole int
+
+const (
+	// RoleUnknown marks a component without a division keyword
+	RoleUnknown AddressRole = iota
+	RoleMunicipality
+	RoleDistrict
+	RoleProvince
+)
+
+// AddressPart is one comma-separated component of an address
+type AddressPart struct {
+	// Raw is the component as written, less any ward reference
+	Raw string
+	// Text is the component with its division keyword removed
+	Text string
+	Role AddressRole
+}
+
+// ParsedAddress is the structure extracted from a free-form address
+type ParsedAddress struct {
+	Ward  *int
+	Parts []AddressPart
+}
+
+// divisionKeywords are the trailing words that name a component's division,
+// longest first so "Rural Municipality" is removed whole
+var divisionKeywords = []struct {
+	keyword string
+	role    AddressRole
+}{
+	{"sub-metropolitan city", RoleMunicipality},
+	{"metropolitan city", RoleMunicipality},
+	{"rural municipality", RoleMunicipality},
+	{"municipality", RoleMunicipality},
+	{"upamahanagarpalika", RoleMunicipality},
+	{"mahanagarpalika", RoleMunicipality},
+	{"nagarpalika", RoleMunicipality},
+	{"gaunpalika", RoleMunicipality},
+	{"उपमहानगरपालिका", RoleMunicipality},
+	{"महानगरपालिका", RoleMunicipality},
+	{"नगरपालिका", RoleMunicipality},
+	{"गाउँपालिका", RoleMunicipality},
+	{"district", RoleDistrict},
+	{"jilla", RoleDistrict},
+	{"जिल्ला", RoleDistrict},
+	{"province", RoleProvince},
+	{"pradesh", RoleProvince},
+	{"प्रदेश", RoleProvince},
+}
+
+// countryNames are components naming the country itself, which carry no information
+var countryNames = map[string]bool{"nepal": true, "नेपाल": true}
+
+// wardSuffixPattern matches the "Lalitpur-5" form of a municipality and ward
+var wardSuffixPattern = regexp.MustCompile(`^(.*\D)\s*-\s*(\d{1,2})$`)
+
+// ParseAddress splits a free-form address such as "Ward no 5, Lalitpur, Bagmati
+// province" into a ward number and named components. Components are separated
+// by commas or semicolons and keep the order they were written in.
+func ParseAddress(text string) ParsedAddress {
+	var parsed ParsedAddress
+
+	segments := strings.FieldsFunc(normalize.DevanagariNumerals(normalize.SanitizeQuery(text)), func(r rune) bool {
+		return r == ',' || r == ';'
+	})
+	for _, segment := range segments {
+		segment, ward := extractWard(strings.TrimSpace(segment))
+		if ward != nil && parsed.Ward == nil {
+			parsed.Ward = ward
+		}
+		if segment == "" || countryNames[strings.ToLower(segment)] {
+			continue
+		}
+
+		part := AddressPart{Raw: segment, Text: segment}
+		for _, k := range divisionKeywords {
+			cut := len(segment) - len(k.keyword)
+			if cut < 0 || !strings.EqualFold(segment[cut:], k.keyword) {
+				continue
+			}
+			if rest := segment[:cut]; rest == "" || strings.HasSuffix(rest, " ") {
+				part.Role = k.role
+				if name := strings.TrimSpace(rest); name != "" {
+					part.Text = name
+				}
+				break
+			}
+		}
+		parsed.Parts = append(parsed.Parts, part)
+	}
+
+	return parsed
+}
+
+// extractWard removes a ward reference ("Ward 5", "वडा नं. 5", "Lalitpur-5")
+// from an address component, returning what is left and the ward number
+func extractWard(segment string) (string, *int) {
+	if match := wardPattern.FindStringSubmatchIndex(segment); match != nil {
+		if n, err := strconv.Atoi(segment[match[2]:match[3]]); err == nil && n > 0 {
+			return strings.TrimSpace(segment[:match[0]] + " " + segment[match[1]:]), &n
+		}
+	}
+	if match := wardSuffixPattern.FindStringSubmatch(segment); match != nil {
+		if n, err := strconv.Atoi(match[2]); err == nil && n > 0 {
+			return strings.TrimSpace(match[1]), &n
+		}
+	}
+	return segment, nil
+}
@@ -0,0 +1,37 @@
+// Package queryparser extracts structured filters from free-text location queries
+package queryparser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// wardPattern matches "Ward 5", "ward no. 5", "Ward-5", "वडा 5" and "वडा नं. 5"
+var wardPattern = regexp.MustCompile(`(?i)(?:^|[\s,])(?:ward|वडा)\s*(?:no\.?|number|नं\.?)?\s*[-#:]?\s*(\d{1,2})(?:[\s,]|$)`)
+
+// whitespacePattern matches runs of whitespace and commas left behind after extraction
+var whitespacePattern = regexp.MustCompile(`[\s,]+`)
+
+// ParseQueryForWard extracts a "Ward N" / "वडा N" reference from q, returning
+// the remaining text and the ward number. If no ward is found, or nothing
+// would be left to search for, q is returned unchanged with a nil ward.
+func ParseQueryForWard(q string) (cleanedQuery string, ward *int) {
+	match := wardPattern.FindStringSubmatchIndex(q)
+	if match == nil {
+		return q, nil
+	}
+
+	n, err := strconv.Atoi(q[match[2]:match[3]])
+	if err != nil || n <= 0 {
+		return q, nil
+	}
+
+	cleaned := q[:match[0]] + " " + q[match[1]:]
+	cleaned = strings.Trim(whitespacePattern.ReplaceAllString(cleaned, " "), " ")
+	if cleaned == "" {
+		return q, nil
+	}
+
+	return cleaned, &n
+}
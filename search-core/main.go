@@ -1,17 +1,187 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/playground"
 	elasticsearch "github.com/elastic/go-elasticsearch/v8"
+	"github.com/redis/go-redis/v9"
 
 	"search-core/graph"
 )
 
+// durationPattern matches Elasticsearch time units such as 365d or 12h
+var durationPattern = regexp.MustCompile(`^\d+(d|h|m|s)$`)
+
+// getEnvInt retrieves an integer environment variable or returns a default
+func getEnvInt(key string, defaultVal int) int {
+	if val := os.Getenv(key); val != "" {
+		if intVal, err := strconv.Atoi(val); err == nil {
+			return intVal
+		}
+	}
+	return defaultVal
+}
+
+// esConfig builds the Elasticsearch client configuration. ES_API_KEY takes
+// precedence over ES_USERNAME/ES_PASSWORD basic auth. The HTTP transport is
+// set explicitly so its idle connections can be closed on shutdown.
+func esConfig(esURL string) (elasticsearch.Config, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	tlsConfig, err := esTLSConfig()
+	if err != nil {
+		return elasticsearch.Config{}, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	cfg := elasticsearch.Config{
+		Addresses: []string{esURL},
+		Transport: transport,
+	}
+
+	switch {
+	case os.Getenv("ES_API_KEY") != "":
+		cfg.APIKey = os.Getenv("ES_API_KEY")
+		log.Println("Elasticsearch auth: API key")
+	case os.Getenv("ES_USERNAME") != "":
+		cfg.Username = os.Getenv("ES_USERNAME")
+		cfg.Password = os.Getenv("ES_PASSWORD")
+		log.Printf("Elasticsearch auth: basic (user %s)", cfg.Username)
+	default:
+		log.Println("Elasticsearch auth: none")
+	}
+
+	return cfg, nil
+}
+
+// esTLSConfig builds the TLS configuration for Elasticsearch. ES_CA_CERT_PATH
+// adds a PEM CA certificate to the trusted roots; ES_TLS_SKIP_VERIFY=true
+// disables certificate verification and is meant for development only.
+func esTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if path := os.Getenv("ES_CA_CERT_PATH"); path != "" {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA certificate: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", path)
+		}
+		tlsConfig.RootCAs = pool
+		log.Printf("Elasticsearch TLS: trusting CA certificate %s", path)
+	}
+
+	if os.Getenv("ES_TLS_SKIP_VERIFY") == "true" {
+		tlsConfig.InsecureSkipVerify = true
+		log.Println("WARNING: Elasticsearch TLS certificate verification disabled (ES_TLS_SKIP_VERIFY=true)")
+	}
+
+	return tlsConfig, nil
+}
+
+// waitForElasticsearch retries the Elasticsearch info call with exponential
+// backoff until it succeeds or timeout elapses
+func waitForElasticsearch(esClient *elasticsearch.Client, timeout time.Duration) error {
+	start := time.Now()
+	backoff := 500 * time.Millisecond
+	const maxBackoff = 8 * time.Second
+
+	for attempt := 1; ; attempt++ {
+		res, err := esClient.Info()
+		if err == nil {
+			res.Body.Close()
+			if !res.IsError() {
+				return nil
+			}
+			err = fmt.Errorf("elasticsearch error: %s", res.Status())
+		}
+
+		elapsed := time.Since(start)
+		if elapsed+backoff > timeout {
+			return err
+		}
+		log.Printf("Elasticsearch not ready (attempt %d, %s elapsed): %v; retrying in %s",
+			attempt, elapsed.Round(time.Second), err, backoff)
+		time.Sleep(backoff)
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// newRedisClient connects to Redis when REDIS_URL is set, returning nil otherwise
+func newRedisClient() *redis.Client {
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		log.Println("REDIS_URL not set, Redis-backed features use in-memory fallbacks or are disabled")
+		return nil
+	}
+
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		log.Fatalf("Error parsing REDIS_URL: %v", err)
+	}
+	redisClient := redis.NewClient(opts)
+
+	// A failed ping is not fatal; the cache falls back to memory until Redis is reachable
+	if err := redisClient.Ping(context.Background()).Err(); err != nil {
+		log.Printf("WARNING: Redis at %s unavailable, using in-memory fallback: %v", opts.Addr, err)
+	} else {
+		log.Printf("Connected to Redis at %s", opts.Addr)
+	}
+
+	return redisClient
+}
+
+// newSearchCache creates the search response cache, backed by Redis when
+// available and by an in-memory LRU otherwise
+func newSearchCache(redisClient *redis.Client, ttl time.Duration) *graph.FallbackCache {
+	size := getEnvInt("CACHE_SIZE", 1000)
+	if redisClient == nil {
+		log.Printf("Using in-memory search cache (size %d)", size)
+		return graph.NewFallbackCache(nil, size, ttl)
+	}
+	return graph.NewFallbackCache(&graph.RedisCache{Client: redisClient}, size, ttl)
+}
+
+// searchVariantsConfig returns the A/B test variant configuration from the file
+// at SEARCH_VARIANTS_FILE, or else from SEARCH_VARIANTS, with where it came from.
+// It returns nil when neither is set.
+func searchVariantsConfig() ([]byte, string) {
+	if path := os.Getenv("SEARCH_VARIANTS_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("Error reading SEARCH_VARIANTS_FILE: %v", err)
+		}
+		return data, path
+	}
+	if spec := os.Getenv("SEARCH_VARIANTS"); spec != "" {
+		return []byte(spec), "SEARCH_VARIANTS"
+	}
+	return nil, ""
+}
+
 func main() {
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -24,40 +194,170 @@ func main() {
 	}
 
 	// Initialize Elasticsearch client
-	cfg := elasticsearch.Config{
-		Addresses: []string{esURL},
+	cfg, err := esConfig(esURL)
+	if err != nil {
+		log.Fatalf("Error configuring Elasticsearch client: %v", err)
 	}
 	esClient, err := elasticsearch.NewClient(cfg)
 	if err != nil {
 		log.Fatalf("Error creating Elasticsearch client: %v", err)
 	}
 
-	// Test Elasticsearch connection
-	res, err := esClient.Info()
-	if err != nil {
-		log.Fatalf("Error getting Elasticsearch info: %v", err)
+	// Wait for Elasticsearch before accepting traffic; it can take a while to start in Docker Compose
+	startupTimeout := time.Duration(getEnvInt("ES_STARTUP_TIMEOUT_SECONDS", 60)) * time.Second
+	if err := waitForElasticsearch(esClient, startupTimeout); err != nil {
+		log.Fatalf("Elasticsearch not available after %s: %v", startupTimeout, err)
 	}
-	res.Body.Close()
 	log.Printf("Connected to Elasticsearch at %s", esURL)
 
+	redisClient := newRedisClient()
+
 	// Create resolver with Elasticsearch client
+	cacheTTL := time.Duration(getEnvInt("CACHE_TTL_SECONDS", 300)) * time.Second
 	resolver := &graph.Resolver{
-		ESClient: esClient,
+		ESClient:    esClient,
+		ESTransport: cfg.Transport,
+		Cache:       newSearchCache(redisClient, cacheTTL),
+		CacheTTL:    cacheTTL,
+
+		DefaultCountry: "NP",
+		DebugFeatures:  os.Getenv("ENABLE_DEBUG_FEATURES") == "true",
+		MinQueryLength: getEnvInt("SEARCH_MIN_QUERY_LENGTH", 2),
+		MaxQueryLength: getEnvInt("MAX_QUERY_LENGTH", 500),
+
+		RoutingOptimization:      os.Getenv("ENABLE_ROUTING_OPTIMIZATION") == "true",
+		AmbiguityScoreGapPercent: getEnvInt("AMBIGUITY_SCORE_GAP_PERCENT", 15),
+
+		StalenessDecayScale: "365d",
+		SearchMaxDuration:   time.Duration(getEnvInt("SEARCH_MAX_DURATION_MS", 5000)) * time.Millisecond,
+	}
+	if country := os.Getenv("ES_DEFAULT_COUNTRY"); country != "" {
+		resolver.DefaultCountry = country
+	}
+	if scale, ok := os.LookupEnv("OSM_STALENESS_DECAY_SCALE"); ok {
+		if scale != "" && !durationPattern.MatchString(scale) {
+			log.Fatalf("Invalid OSM_STALENESS_DECAY_SCALE %q (expected a duration such as 365d)", scale)
+		}
+		resolver.StalenessDecayScale = scale
+	}
+
+	if spec := os.Getenv("ES_FIELD_BOOSTS"); spec != "" {
+		boosts, err := graph.ParseFieldBoosts(spec)
+		if err != nil {
+			log.Fatalf("Invalid ES_FIELD_BOOSTS: %v", err)
+		}
+		resolver.FieldBoosts = boosts
+		log.Printf("Field boost overrides: %s", spec)
+	}
+
+	if variants, source := searchVariantsConfig(); variants != nil {
+		parsed, err := graph.ParseSearchVariants(variants)
+		if err != nil {
+			log.Fatalf("Invalid search variants in %s: %v", source, err)
+		}
+		resolver.SearchVariants = parsed
+		log.Printf("Search variants loaded from %s", source)
+	}
+
+	switch ranker := os.Getenv("SEARCH_RANKER"); ranker {
+	case "boost_score":
+		resolver.Ranker = graph.BoostScoreRanker{}
+	case "", "none":
+	default:
+		log.Fatalf("Unknown SEARCH_RANKER %q (expected boost_score or none)", ranker)
+	}
+
+	if os.Getenv("ENABLE_SEARCH_LOGGING") == "true" {
+		resolver.SearchLogger = graph.NewSearchLogger(esClient, getEnvInt("SEARCH_LOG_BUFFER_SIZE", 1000))
+		log.Println("Search logging enabled")
+	}
+
+	if os.Getenv("ENABLE_AUDIT_LOG") != "false" {
+		resolver.AuditLogger = graph.NewAuditLogger(esClient, getEnvInt("AUDIT_LOG_BUFFER_SIZE", 1000))
+		log.Println("Audit logging enabled")
+	}
+
+	if redisClient != nil {
+		resolver.PubSub = redisClient
+		resolver.SessionStore = &graph.RedisSessionStore{
+			Client:     redisClient,
+			MaxEntries: getEnvInt("RECENT_SEARCHES_MAX", 10),
+		}
 	}
 
 	// Create GraphQL server
-	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: resolver}))
+	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{
+		Resolvers:  resolver,
+		Directives: graph.DirectiveRoot{Admin: graph.AdminDirective},
+	}))
+	if complexityLimit := getEnvInt("GRAPHQL_COMPLEXITY_LIMIT", 500); complexityLimit > 0 {
+		srv.Use(extension.FixedComplexityLimit(complexityLimit))
+	}
+	srv.AroundOperations(graph.CacheControlMiddleware)
 
 	// Register handlers
 	http.Handle("/", playground.Handler("GraphQL playground", "/graphql"))
-	http.Handle("/graphql", srv)
+	var graphqlHandler http.Handler = srv
+	trustedProxies, err := parseCIDRs(os.Getenv("TRUSTED_PROXY_CIDRS"))
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXY_CIDRS: %v", err)
+	}
+	if rps := getEnvInt("RATE_LIMIT_RPS", 0); rps > 0 {
+		burst := getEnvInt("RATE_LIMIT_BURST", 20)
+		graphqlHandler = rateLimitMiddleware(float64(rps), burst, trustedProxies, graphqlHandler)
+		log.Printf("Rate limiting enabled: %d req/s per IP (burst %d)", rps, burst)
+	}
+	graphqlHandler = adminMiddleware(os.Getenv("ADMIN_API_KEY"), actorMiddleware(trustedProxies, graphqlHandler))
+	if os.Getenv("ADMIN_API_KEY") == "" {
+		log.Println("ADMIN_API_KEY not set, admin operations are disabled")
+	}
+	http.Handle("/graphql", responseHeadersMiddleware(graphqlHandler))
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.Write([]byte(`{"status":"healthy","elasticsearch":"connected"}`))
 	})
 
+	var rootHandler http.Handler = http.DefaultServeMux
+	if minSize := getEnvInt("GZIP_MIN_SIZE", 1024); minSize >= 0 {
+		rootHandler = gzipMiddleware(minSize, rootHandler)
+	}
+	if origins := parseAllowedOrigins(os.Getenv("CORS_ALLOWED_ORIGINS")); len(origins) > 0 {
+		rootHandler = corsMiddleware(origins, rootHandler)
+		log.Printf("CORS enabled for origins: %s", strings.Join(origins, ", "))
+	}
+
+	if os.Getenv("ENABLE_PPROF") == "true" {
+		pprofPort := os.Getenv("PPROF_PORT")
+		if pprofPort == "" {
+			pprofPort = "6060"
+		}
+		startPprofServer(pprofPort)
+	}
+
 	log.Printf("Server starting on :%s", port)
 	log.Printf("GraphQL endpoint: http://localhost:%s/graphql", port)
 	log.Printf("GraphQL playground: http://localhost:%s/", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+
+	server := &http.Server{Addr: ":" + port, Handler: rootHandler}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Server error: %v", err)
+		}
+	}()
+
+	// Drain in-flight requests on SIGINT/SIGTERM, then release ES connections
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	log.Println("Shutting down...")
+	shutdownTimeout := time.Duration(getEnvInt("SHUTDOWN_TIMEOUT_SECONDS", 10)) * time.Second
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("WARNING: graceful shutdown failed: %v", err)
+	}
+	if err := resolver.Close(); err != nil {
+		log.Printf("WARNING: error closing resolver: %v", err)
+	}
 }
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"net/http/pprof"
+)
+
+// startPprofServer serves the pprof endpoints on a separate admin port so
+// profiling data is never reachable through the public listener. The handlers
+// are registered on their own mux rather than through the blank import, which
+// would add them to http.DefaultServeMux (the public mux).
+//
+// To collect a 30-second CPU profile:
+//
+//	go tool pprof "http://localhost:6060/debug/pprof/profile?seconds=30"
+//
+// Heap and goroutine profiles are at /debug/pprof/heap and /debug/pprof/goroutine.
+func startPprofServer(port string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	go func() {
+		log.Printf("pprof endpoint: http://localhost:%s/debug/pprof/", port)
+		if err := http.ListenAndServe(":"+port, mux); err != nil {
+			log.Printf("WARNING: pprof server stopped: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// visitorIdleTimeout is how long an idle client's limiter is kept before cleanup
+const visitorIdleTimeout = 3 * time.Minute
+
+type visitor struct {
+	limiter  *rate.Limiter
+	lastSeen atomic.Int64
+}
+
+// ipRateLimiter applies a token-bucket limit per client IP
+type ipRateLimiter struct {
+	visitors sync.Map
+	rps      rate.Limit
+	burst    int
+}
+
+func newIPRateLimiter(rps float64, burst int) *ipRateLimiter {
+	l := &ipRateLimiter{
+		rps:   rate.Limit(rps),
+		burst: burst,
+	}
+	go l.cleanup()
+	return l
+}
+
+// limiter returns the limiter for an IP, creating it on first use
+func (l *ipRateLimiter) limiter(ip string) *rate.Limiter {
+	v, ok := l.visitors.Load(ip)
+	if !ok {
+		v, _ = l.visitors.LoadOrStore(ip, &visitor{limiter: rate.NewLimiter(l.rps, l.burst)})
+	}
+	vis := v.(*visitor)
+	vis.lastSeen.Store(time.Now().Unix())
+	return vis.limiter
+}
+
+// cleanup periodically drops limiters for clients that have gone idle
+func (l *ipRateLimiter) cleanup() {
+	for range time.Tick(time.Minute) {
+		cutoff := time.Now().Add(-visitorIdleTimeout).Unix()
+		l.visitors.Range(func(key, value any) bool {
+			if value.(*visitor).lastSeen.Load() < cutoff {
+				l.visitors.Delete(key)
+			}
+			return true
+		})
+	}
+}
+
+// rateLimitMiddleware rejects requests over the per-IP limit with 429 Too
+// Many Requests. Clients are identified as described in clientIP.
+func rateLimitMiddleware(rps float64, burst int, trustedProxies []*net.IPNet, next http.Handler) http.Handler {
+	limiter := newIPRateLimiter(rps, burst)
+	retryAfter := strconv.Itoa(int(math.Ceil(1 / rps)))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !limiter.limiter(clientIP(r, trustedProxies)).Allow() {
+			w.Header().Set("Retry-After", retryAfter)
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the client address. The X-Real-Ip header set by Traefik
+// is only used when the request comes from one of trustedProxies, since any
+// client can send it; otherwise the connection's remote address is used.
+func clientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	host := remoteHost(r)
+	if !fromTrustedProxy(r, trustedProxies) {
+		return host
+	}
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-Ip"))); ip != nil {
+		return ip.String()
+	}
+	return host
+}
+
+// remoteHost returns the host of the connection's remote address
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// fromTrustedProxy reports whether the request's connection comes from one of
+// trustedProxies, whose forwarded headers can be believed
+func fromTrustedProxy(r *http.Request, trustedProxies []*net.IPNet) bool {
+	remote := net.ParseIP(remoteHost(r))
+	return remote != nil && containsIP(trustedProxies, remote)
+}
+
+// containsIP reports whether ip is in any of the networks
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, n := range networks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCIDRs parses a comma-separated TRUSTED_PROXY_CIDRS value such as
+// "172.18.0.0/16,10.0.0.5/32"
+func parseCIDRs(spec string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", entry, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	proxies, err := parseCIDRs("172.18.0.0/16, 10.0.0.5/32")
+	if err != nil {
+		t.Fatalf("parseCIDRs: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		realIP     string
+		want       string
+	}{
+		{"direct client", "203.0.113.7:51234", "", "203.0.113.7"},
+		{"direct client spoofing the header", "203.0.113.7:51234", "198.51.100.1", "203.0.113.7"},
+		{"trusted proxy", "172.18.0.3:40000", "198.51.100.1", "198.51.100.1"},
+		{"trusted single-host proxy", "10.0.0.5:40000", "198.51.100.2", "198.51.100.2"},
+		{"trusted proxy without the header", "172.18.0.3:40000", "", "172.18.0.3"},
+		{"trusted proxy with an invalid header", "172.18.0.3:40000", "not-an-ip", "172.18.0.3"},
+		{"IPv6 client", "[2001:db8::1]:443", "198.51.100.1", "2001:db8::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-Ip", tt.realIP)
+			}
+			if got := clientIP(r, proxies); got != tt.want {
+				t.Errorf("clientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCIDRsRejectsInvalid(t *testing.T) {
+	if _, err := parseCIDRs("172.18.0.0/16,172.18.0.1"); err == nil {
+		t.Error("parseCIDRs accepted an address without a prefix length")
+	}
+}
+
+func TestRateLimitMiddlewareIgnoresSpoofedHeader(t *testing.T) {
+	handler := rateLimitMiddleware(1, 2, nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	codes := make([]int, 3)
+	for i := range codes {
+		r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+		r.RemoteAddr = "203.0.113.7:51234"
+		// A new header value per request must not buy a new bucket
+		r.Header.Set("X-Real-Ip", "198.51.100."+string(rune('1'+i)))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		codes[i] = rec.Code
+		if rec.Code == http.StatusTooManyRequests && rec.Header().Get("Retry-After") != "1" {
+			t.Errorf("Retry-After = %q, want 1", rec.Header().Get("Retry-After"))
+		}
+	}
+
+	want := []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}
+	for i := range want {
+		if codes[i] != want[i] {
+			t.Fatalf("status codes = %v, want %v", codes, want)
+		}
+	}
+}
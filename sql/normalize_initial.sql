@@ -53,11 +53,14 @@ CREATE TABLE IF NOT EXISTS normalized.named_roads (
   id SERIAL PRIMARY KEY,
   osm_id BIGINT,
   name TEXT,
+  highway TEXT,
   tags HSTORE,
   geom GEOMETRY(LineString,4326),
   length_m DOUBLE PRECISION,
   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
+-- Added after the table was first created; osm2pgsql --hstore keeps highway out of tags
+ALTER TABLE normalized.named_roads ADD COLUMN IF NOT EXISTS highway TEXT;
 CREATE INDEX IF NOT EXISTS n_idx_roads_geom ON normalized.named_roads USING GIST (geom);
 CREATE INDEX IF NOT EXISTS n_idx_roads_name ON normalized.named_roads (name);
 
@@ -66,10 +69,20 @@ CREATE TABLE IF NOT EXISTS normalized.poi (
   id SERIAL PRIMARY KEY,
   osm_id BIGINT,
   name TEXT,
+  amenity TEXT,
+  shop TEXT,
+  tourism TEXT,
+  leisure TEXT,
   tags HSTORE,
   geom GEOMETRY(Point,4326),
   created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
+-- Added after the table was first created; osm2pgsql --hstore keeps these keys out of tags
+ALTER TABLE normalized.poi
+  ADD COLUMN IF NOT EXISTS amenity TEXT,
+  ADD COLUMN IF NOT EXISTS shop TEXT,
+  ADD COLUMN IF NOT EXISTS tourism TEXT,
+  ADD COLUMN IF NOT EXISTS leisure TEXT;
 CREATE INDEX IF NOT EXISTS n_idx_poi_geom ON normalized.poi USING GIST (geom);
 CREATE INDEX IF NOT EXISTS n_idx_poi_name ON normalized.poi (name);
 CREATE INDEX IF NOT EXISTS n_idx_poi_tags ON normalized.poi USING GIN (tags);
@@ -133,10 +146,11 @@ FROM planet_osm_polygon
 WHERE (place IS NOT NULL) OR (tags ? 'place');
 
 -- Named roads: ways with a name and highway tag (use dedicated columns if present)
-INSERT INTO normalized.named_roads (osm_id, name, tags, geom, length_m)
+INSERT INTO normalized.named_roads (osm_id, name, highway, tags, geom, length_m)
 SELECT
   osm_id,
   COALESCE(name, tags->'name') AS name,
+  COALESCE(highway, tags->'highway') AS highway,
   tags::hstore AS tags,
   (ST_Transform(ST_LineMerge(ST_Multi(way)),4326))::geometry(LineString,4326) AS geom,
   ST_Length(ST_Transform(ST_LineMerge(ST_Multi(way)), 3857)) AS length_m
@@ -144,10 +158,14 @@ FROM planet_osm_line
 WHERE ( (highway IS NOT NULL) OR (tags ? 'highway') ) AND ( (name IS NOT NULL) OR (tags ? 'name') );
 
 -- POIs: points with a name and not a 'place'
-INSERT INTO normalized.poi (osm_id, name, tags, geom)
+INSERT INTO normalized.poi (osm_id, name, amenity, shop, tourism, leisure, tags, geom)
 SELECT
   osm_id,
   COALESCE(name, tags->'name') AS name,
+  COALESCE(amenity, tags->'amenity') AS amenity,
+  COALESCE(shop, tags->'shop') AS shop,
+  COALESCE(tourism, tags->'tourism') AS tourism,
+  COALESCE(leisure, tags->'leisure') AS leisure,
   tags::hstore AS tags,
   ST_Transform(way,4326) AS geom
 FROM planet_osm_point